@@ -0,0 +1,293 @@
+package bubbleboxer
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Border is a set of characters which are used to draw the separators and the junctions where they meet.
+// All characters should be one column wide.
+type Border struct {
+	// Horizontal is a horizontal line (─), which separates vertical stacked children.
+	Horizontal string `json:"horizontal" yaml:"horizontal"`
+	// Vertical is a vertical line (│), which separates horizontal arranged children.
+	Vertical string `json:"vertical" yaml:"vertical"`
+
+	TopLeft     string `json:"topLeft" yaml:"topLeft"`         // ┌
+	TopRight    string `json:"topRight" yaml:"topRight"`       // ┐
+	BottomLeft  string `json:"bottomLeft" yaml:"bottomLeft"`   // └
+	BottomRight string `json:"bottomRight" yaml:"bottomRight"` // ┘
+
+	MiddleLeft   string `json:"middleLeft" yaml:"middleLeft"`     // ├
+	MiddleRight  string `json:"middleRight" yaml:"middleRight"`   // ┤
+	MiddleTop    string `json:"middleTop" yaml:"middleTop"`       // ┬
+	MiddleBottom string `json:"middleBottom" yaml:"middleBottom"` // ┴
+	Middle       string `json:"middle" yaml:"middle"`             // ┼
+}
+
+// NormalBorder returns a Border with light lines and square corners.
+func NormalBorder() Border {
+	return Border{
+		Horizontal: "─", Vertical: "│",
+		TopLeft: "┌", TopRight: "┐", BottomLeft: "└", BottomRight: "┘",
+		MiddleLeft: "├", MiddleRight: "┤", MiddleTop: "┬", MiddleBottom: "┴", Middle: "┼",
+	}
+}
+
+// RoundedBorder returns a Border with light lines and rounded corners.
+func RoundedBorder() Border {
+	b := NormalBorder()
+	b.TopLeft, b.TopRight, b.BottomLeft, b.BottomRight = "╭", "╮", "╰", "╯"
+	return b
+}
+
+// ThickBorder returns a Border with heavy lines.
+func ThickBorder() Border {
+	return Border{
+		Horizontal: "━", Vertical: "┃",
+		TopLeft: "┏", TopRight: "┓", BottomLeft: "┗", BottomRight: "┛",
+		MiddleLeft: "┣", MiddleRight: "┫", MiddleTop: "┳", MiddleBottom: "┻", Middle: "╋",
+	}
+}
+
+// DoubleBorder returns a Border with double lines.
+func DoubleBorder() Border {
+	return Border{
+		Horizontal: "═", Vertical: "║",
+		TopLeft: "╔", TopRight: "╗", BottomLeft: "╚", BottomRight: "╝",
+		MiddleLeft: "╠", MiddleRight: "╣", MiddleTop: "╦", MiddleBottom: "╩", Middle: "╬",
+	}
+}
+
+// DashedBorder returns a Border with dashed light lines.
+// Since there are no dashed junction characters the light ones are used where the lines meet.
+func DashedBorder() Border {
+	b := NormalBorder()
+	b.Horizontal, b.Vertical = "╌", "╎"
+	return b
+}
+
+// ASCIIBorder returns a Border which only uses ASCII characters, for terminals without unicode support.
+func ASCIIBorder() Border {
+	return Border{
+		Horizontal: "-", Vertical: "|",
+		TopLeft: "+", TopRight: "+", BottomLeft: "+", BottomRight: "+",
+		MiddleLeft: "+", MiddleRight: "+", MiddleTop: "+", MiddleBottom: "+", Middle: "+",
+	}
+}
+
+// knownBorders are the character sets which are matched against the package-level separators
+var knownBorders = []func() Border{
+	NormalBorder,
+	ThickBorder,
+	DoubleBorder,
+	DashedBorder,
+	ASCIIBorder,
+}
+
+// separatorBorder returns the Border which matches the package-level separators.
+// If no known Border matches, the separators are also used where they meet.
+func separatorBorder() Border {
+	for _, known := range knownBorders {
+		if b := known(); b.Horizontal == VerticalSeparator && b.Vertical == HorizontalSeparator {
+			return b
+		}
+	}
+	return Border{
+		Horizontal: VerticalSeparator, Vertical: HorizontalSeparator,
+		TopLeft: VerticalSeparator, TopRight: VerticalSeparator, BottomLeft: VerticalSeparator, BottomRight: VerticalSeparator,
+		MiddleLeft: HorizontalSeparator, MiddleRight: HorizontalSeparator, MiddleTop: VerticalSeparator, MiddleBottom: VerticalSeparator, Middle: HorizontalSeparator,
+	}
+}
+
+// border returns the Border used for the separators of the layout-tree, which are not overridden by a node.
+func (b *Boxer) border() Border {
+	if b.Border != nil {
+		return *b.Border
+	}
+	return separatorBorder()
+}
+
+// arms are the directions in which a separator cell connects to its neighbours
+type arms uint8
+
+const (
+	armUp arms = 1 << iota
+	armDown
+	armLeft
+	armRight
+)
+
+// glyph returns the character of the Border which connects in the directions of the arms.
+func (b Border) glyph(a arms) string {
+	switch a {
+	case armUp | armDown | armLeft | armRight:
+		return b.Middle
+	case armUp | armDown | armRight:
+		return b.MiddleLeft
+	case armUp | armDown | armLeft:
+		return b.MiddleRight
+	case armLeft | armRight | armDown:
+		return b.MiddleTop
+	case armLeft | armRight | armUp:
+		return b.MiddleBottom
+	case armDown | armRight:
+		return b.TopLeft
+	case armDown | armLeft:
+		return b.TopRight
+	case armUp | armRight:
+		return b.BottomLeft
+	case armUp | armLeft:
+		return b.BottomRight
+	}
+	if a&(armUp|armDown) == 0 {
+		return b.Horizontal
+	}
+	return b.Vertical
+}
+
+// point is an absolute position on the screen
+type point struct {
+	x, y int
+}
+
+// junction is a separator cell with the directions in which it connects
+// and the Border and style of the node which draws it.
+type junction struct {
+	arms arms
+	look look
+}
+
+// look is how the separators of a node are drawn.
+type look struct {
+	border Border
+	// style is nil if the separators are not styled
+	style *lipgloss.Style
+}
+
+// topology holds all separator cells of the layout-tree.
+type topology map[point]junction
+
+// mark adds the arms to the separator cell at the position, drawn with the given look.
+func (t topology) mark(p point, a arms, l look) {
+	t[p] = junction{arms: t[p].arms | a, look: l}
+}
+
+// topology collects the separator cells of the whole layout-tree and connects the neighbouring ones,
+// so that the junctions can be drawn where separators of different nodes meet.
+// The look is used for all nodes which do not override it.
+func (n *Node) topology(l look) topology {
+	t := make(topology)
+	n.separators(t, l)
+
+	// connect the separator cells which point to each other,
+	// which can be done in place since a arm is only added if the neighbour has the opposite arm already
+	for p, j := range t {
+		if t[point{p.x, p.y - 1}].arms&armDown != 0 {
+			j.arms |= armUp
+		}
+		if t[point{p.x, p.y + 1}].arms&armUp != 0 {
+			j.arms |= armDown
+		}
+		if t[point{p.x - 1, p.y}].arms&armRight != 0 {
+			j.arms |= armLeft
+		}
+		if t[point{p.x + 1, p.y}].arms&armLeft != 0 {
+			j.arms |= armRight
+		}
+		t[p] = j
+	}
+	return t
+}
+
+// separators recursively adds the separator cells of this node and its descendants to the topology.
+// The Border and the style are inherited from the parent if the node does not override them.
+func (n *Node) separators(t topology, l look) {
+	if n.Border != nil {
+		l.border = *n.Border
+	}
+	if n.SeparatorStyle != nil {
+		l.style = n.SeparatorStyle
+	}
+	for i := range n.Children {
+		n.Children[i].separators(t, l)
+	}
+	n.frameSeparators(t, l)
+	if n.noBorder || len(n.Children) < 2 {
+		return
+	}
+	if n.Grid != nil {
+		n.gridSeparators(t, l)
+		return
+	}
+	inner := n.ContentRect()
+	for _, c := range n.Children[:len(n.Children)-1] {
+		if n.VerticalStacked {
+			for x := inner.X; x < inner.X+inner.Width; x++ {
+				t.mark(point{x, c.y + c.height}, armLeft|armRight, l)
+			}
+			continue
+		}
+		for y := inner.Y; y < inner.Y+inner.Height; y++ {
+			t.mark(point{c.x + c.width, y}, armUp|armDown, l)
+		}
+	}
+}
+
+// renderPass holds the state which is shared by all nodes while rendering the layout-tree.
+type renderPass struct {
+	modelMap map[string]tea.Model
+
+	// topology holds the separator cells of the whole layout-tree
+	topology topology
+
+	// focusStyle is applied to the separator cells next to the focused area, if both are set
+	focused    *Rect
+	focusStyle *lipgloss.Style
+
+	// measure is used to measure the width of the lines
+	measure measure
+
+	// errorView renders the leaves which fail to render, if it is nil the error is returned instead
+	errorView ErrorView
+}
+
+// separator returns the character of the separator at the absolute position.
+func (p *renderPass) separator(x, y int) string {
+	return p.horizontalLine(x, y, 1)
+}
+
+// style returns the style of the separator cell at the absolute position or nil if it is not styled.
+func (p *renderPass) style(x, y int) *lipgloss.Style {
+	if p.focused != nil && p.focusStyle != nil && p.focused.borders(x, y) {
+		return p.focusStyle
+	}
+	return p.topology[point{x, y}].look.style
+}
+
+// horizontalLine returns the characters of a horizontal separator from the absolute position on with the given width.
+// Neighbouring cells with the same style are rendered together.
+func (p *renderPass) horizontalLine(x, y, width int) string {
+	var line, run strings.Builder
+	var runStyle *lipgloss.Style
+	flush := func() {
+		if runStyle != nil {
+			line.WriteString(runStyle.Render(run.String()))
+		} else {
+			line.WriteString(run.String())
+		}
+		run.Reset()
+	}
+	for i := 0; i < width; i++ {
+		j := p.topology[point{x + i, y}]
+		if style := p.style(x+i, y); style != runStyle {
+			flush()
+			runStyle = style
+		}
+		run.WriteString(j.look.border.glyph(j.arms))
+	}
+	flush()
+	return line.String()
+}
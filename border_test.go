@@ -0,0 +1,112 @@
+package bubbleboxer
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestJunctions(t *testing.T) {
+	b := Boxer{}
+	b.LayoutTree = Node{
+		Children: []Node{
+			stripErr(b.CreateLeaf("a", testModel("a"))),
+			{
+				VerticalStacked: true,
+				Children: []Node{
+					{Children: []Node{
+						stripErr(b.CreateLeaf("b", testModel("b"))),
+						stripErr(b.CreateLeaf("c", testModel("c"))),
+					}},
+					stripErr(b.CreateLeaf("d", testModel("d"))),
+				},
+			},
+		},
+	}
+	if _, err := b.UpdateSize(tea.WindowSizeMsg{Width: 7, Height: 3}); err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Join([]string{
+		"a  │b│c",
+		"   ├─┴─",
+		"   │d  ",
+	}, NEWLINE)
+	if got := b.View(); got != want {
+		t.Errorf("expected:\n%s\nbut got:\n%s", want, got)
+	}
+
+	b.LayoutTree = Node{
+		VerticalStacked: true,
+		Children: []Node{
+			{Children: []Node{
+				stripErr(b.CreateLeaf("a", testModel("a"))),
+				stripErr(b.CreateLeaf("b", testModel("b"))),
+			}},
+			{Children: []Node{
+				stripErr(b.CreateLeaf("c", testModel("c"))),
+				stripErr(b.CreateLeaf("d", testModel("d"))),
+			}},
+		},
+	}
+	if _, err := b.UpdateSize(tea.WindowSizeMsg{Width: 3, Height: 3}); err != nil {
+		t.Fatal(err)
+	}
+	want = strings.Join([]string{
+		"a│b",
+		"─┼─",
+		"c│d",
+	}, NEWLINE)
+	if got := b.View(); got != want {
+		t.Errorf("expected:\n%s\nbut got:\n%s", want, got)
+	}
+}
+
+func TestBorders(t *testing.T) {
+	b := Boxer{}
+	rounded := RoundedBorder()
+	b.Border = &rounded
+	thick := ThickBorder()
+	b.LayoutTree = Node{
+		VerticalStacked: true,
+		Children: []Node{
+			{Children: []Node{
+				stripErr(b.CreateLeaf("a", testModel("a"))),
+				stripErr(b.CreateLeaf("b", testModel("b"))),
+			}},
+			{
+				Border: &thick,
+				Children: []Node{
+					stripErr(b.CreateLeaf("c", testModel("c"))),
+					stripErr(b.CreateLeaf("d", testModel("d"))),
+				},
+			},
+		},
+	}
+	if _, err := b.UpdateSize(tea.WindowSizeMsg{Width: 3, Height: 3}); err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Join([]string{
+		"a│b",
+		"─┼─",
+		"c┃d",
+	}, NEWLINE)
+	if got := b.View(); got != want {
+		t.Errorf("expected:\n%s\nbut got:\n%s", want, got)
+	}
+
+	ascii := ASCIIBorder()
+	b.Border = &ascii
+	b.LayoutTree.Children[1].Border = nil
+	want = strings.Join([]string{
+		"a|b",
+		"-+-",
+		"c|d",
+	}, NEWLINE)
+	if got := b.View(); got != want {
+		t.Errorf("expected:\n%s\nbut got:\n%s", want, got)
+	}
+	if HorizontalSeparator != "│" || VerticalSeparator != "─" {
+		t.Error("the Border of a Boxer should not change the package-level separators")
+	}
+}
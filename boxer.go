@@ -2,10 +2,11 @@ package bubbleboxer
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/muesli/ansi"
+	"github.com/charmbracelet/lipgloss"
 )
 
 var (
@@ -14,7 +15,8 @@ var (
 	// SPACE is used to fill up the lines, make sure it is only one column wide and a single character
 	SPACE = " "
 	// HorizontalSeparator is used to make a visible border between the horizontal arranged children
-	// in the layout-tree, make sure it is only one column wide and a single character
+	// in the layout-tree, make sure it is only one column wide and a single character.
+	// The separators are only used as default for Boxer's without a Border.
 	HorizontalSeparator = "│"
 	// VerticalSeparator is used to make a visible border between the vertical arranged children
 	// in the layout-tree, make sure it is only one column wide and a single character
@@ -25,18 +27,71 @@ var (
 // according to a LayoutTree.
 // The Model's are kept separate from the LayoutTree
 // so that changing a Model does not require traversing the LayoutTree.
+// The methods which change the LayoutTree (like Remove or Split) update the size right away with the size of the last UpdateSize
+// and return the commands the Models returned while they were resized.
 type Boxer struct {
 
 	// LayoutTree holds the root node and thus the hole LayoutTree
 	// Change it as you like as long as every node without children was
 	// created with CreateLeaf (to make sure that every leave has a corresponding ModelMap entry)
 	// After deleting a Leaf delete the corresponding entry from ModelMap if you care about memory-leaks
+	// or use the methods like Remove and Replace, which do it for you.
 	LayoutTree Node
 
+	// Collapse lets Remove replace the ancestors which are left with a single child by this child,
+	// unless they have a Frame, Border or SeparatorStyle.
+	Collapse bool
+
 	// ModelMap is a mapping between the Address of a Leaf and the according Model.
 	// A valid entry can only be created with CreateLeaf,
 	// because entries without a corresponding Node in the LayoutTree are meaningless.
 	ModelMap map[string]tea.Model
+
+	// focus holds the address of the leaf which receives the tea.KeyMsg's (see Focus)
+	focus string
+
+	// Constraints are relations between the edges of the nodes, which are satisfied while updating the size.
+	// They are optional and take precedence over the SizeFunc's and Sizing's of the nodes.
+	Constraints []Constraint
+
+	// Border is the set of characters used to draw the separators of the LayoutTree (see NormalBorder etc.).
+	// If it is nil the package-level separators are used.
+	Border *Border
+
+	// SeparatorStyle is applied to the separators of the LayoutTree which are not styled by a node.
+	SeparatorStyle *lipgloss.Style
+
+	// FocusStyle is applied to the separators around the focused leaf (see Focus),
+	// so that the active pane can be seen at a glance.
+	FocusStyle *lipgloss.Style
+
+	// LocalErrors replaces only the leaves which can not be rendered with a error box (see ErrorView),
+	// instead of replacing the whole View with the error.
+	LocalErrors bool
+
+	// ErrorView renders the error box of a leaf if LocalErrors is set.
+	// If it is nil DefaultErrorView is used.
+	ErrorView ErrorView
+
+	// WidthFunc measures the width of the text of the Models, it is used to check, pad and clip there lines.
+	// If it is nil GraphemeWidth is used.
+	WidthFunc WidthFunc
+
+	// TabWidth is the distance between the tab stops, to which the tabs in the Views of the Models are expanded.
+	// If it is zero or less TabWidth (the constant) is used.
+	TabWidth int
+
+	// initialized holds the addresses of the Models whose Init method was already called
+	initialized map[string]bool
+
+	// lastSize is the size of the last UpdateSize, it is used to re-layout after the LayoutTree was changed
+	lastSize tea.WindowSizeMsg
+
+	// solver is kept between the updates of the size, so that it only has to be rebuild if the layout changes
+	solver *layoutSolver
+
+	// drag is the separator which is dragged with the mouse at the moment
+	drag *drag
 }
 
 // Node is a node in a layout tree or when created with CreateLeaf its a valid leave of the LayoutTree
@@ -46,43 +101,119 @@ type Node struct {
 	// VerticalStacked specifies the orientation of the Children to each other
 	VerticalStacked bool
 
+	// Grid arranges the Children in rows and columns instead of a single row or column (see CreateGridNode).
+	// If it is set VerticalStacked and SizeFunc are ignored.
+	Grid *Grid
+
 	// SizeFunc specifies the width or height (depending on the orientation) provided to each child.
 	// Here by should the sum of the returned int's be the same as the argument 'widthOrHeight'.
 	// The length of the returned slice should be the same as the amount of children of the node argument.
 	SizeFunc func(node Node, widthOrHeight int) []int
 
+	// Sizing declares the width or height (depending on the orientation of the parent) of this node.
+	// It is only used if the parent has no SizeFunc.
+	// If no child of a node has a Sizing set, the space is shared evenly between them.
+	Sizing Sizing
+
+	// Sizes are the sizes of the children set by dragging the separators with the mouse (see MoveSeparator).
+	// They override the SizeFunc and the Sizing's of the children and are scaled proportionally if the available space changes.
+	// They are ignored if the amount of children changed and are removed by ResetSizes.
+	Sizes []int
+
+	// Border overrides the Border of the Boxer for the separators of this node and its descendants.
+	Border *Border
+
+	// SeparatorStyle overrides the SeparatorStyle of the Boxer for this node and its descendants.
+	SeparatorStyle *lipgloss.Style
+
+	// Frame draws a box around the node or leaf, with a optional title and footer.
+	// The cells of the frame are not available for the children or the Model.
+	Frame *Frame
+
+	// Overflow decides what happens if the Model of a leaf returns a View which does not fit into the leaf.
+	// By default it is a error.
+	Overflow Overflow
+
+	// Background fills the whole area of a leaf with this color, including the space the Model does not use.
+	// It is only used for leaves.
+	Background lipgloss.TerminalColor
+
 	// noBorder is private because when it changes, the descendants size has to be changed as well
 	noBorder bool
 
+	// factory is the name of the factory in the Registry which made the Model of the leaf (see Build)
+	factory string
+
 	// address is private so that it can only be set if a corresponding entry in Boxer.ModelMap is created (see CreateLeaf)
 	address string
 
+	// x and y are the absolute position of the upper left corner and are set while updating the size
+	x int
+	y int
+
 	width  int
 	height int
-}
-
-// SizeError conveys that for at leased one node or leaf in the Layout-tree there was not enough space left
-type SizeError error
 
-// NotFoundError convey that the address was not found.
-type NotFoundError error
+	// gridRows and gridColumns hold the sizes of the tracks of a grid node
+	gridRows    []int
+	gridColumns []int
+}
 
-// Init satisfies the tea.Model interface
-func (b Boxer) Init() tea.Cmd { return nil }
+// Init satisfies the tea.Model interface and returns the batched commands of the Init methods of all Models.
+func (b Boxer) Init() tea.Cmd { return b.initModels() }
 
 // Update handles WindowSizeMsg and ctrl+c
+// and forwards all other tea.KeyMsg's to the Model of the focused leaf.
+// A tea.MouseMsg is delivered to the leaf under the pointer with coordinates relative to this leaf,
+// or reported as SeparatorMsg if the pointer is on a separator, which can then be dragged to resize the children next to it.
+// Models which were added after Init was called are initialized before the msg is handled.
+// The commands of the Models are wrapped, so that their resulting messages arrive as AddressedMsg
+// and are delivered back to the Model which issued the command.
 func (b Boxer) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	initCmd := b.initModels()
+	return b, tea.Batch(initCmd, b.handle(msg))
+}
+
+// handle reacts to the msg and returns the resulting commands.
+func (b *Boxer) handle(msg tea.Msg) tea.Cmd {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "ctrl+c":
-			return b, tea.Quit
+			return tea.Quit
 		}
+		return b.updateModel(b.focus, msg)
+	case tea.MouseMsg:
+		return b.routeMouse(msg)
 	case tea.WindowSizeMsg:
-		_ = b.UpdateSize(msg)
-		return b, nil
+		cmd, _ := b.UpdateSize(msg)
+		return cmd
+	case AddressedMsg:
+		return b.updateModel(msg.Address, msg.Msg)
+	}
+	return nil
+}
+
+// initModels calls Init on all Models which were not initialized yet and returns the batched commands.
+func (b *Boxer) initModels() tea.Cmd {
+	if b.initialized == nil {
+		b.initialized = make(map[string]bool)
+	}
+	addresses := make([]string, 0, len(b.ModelMap))
+	for address := range b.ModelMap {
+		if !b.initialized[address] {
+			addresses = append(addresses, address)
+		}
+	}
+	// keep the order of the commands stable
+	sort.Strings(addresses)
+
+	cmds := make([]tea.Cmd, 0, len(addresses))
+	for _, address := range addresses {
+		b.initialized[address] = true
+		cmds = append(cmds, wrapCmd(address, b.ModelMap[address].Init()))
 	}
-	return b, nil
+	return tea.Batch(cmds...)
 }
 
 // View renders the contained tea.Model's according to the LayoutTree
@@ -90,160 +221,214 @@ func (b Boxer) View() string {
 	if b.LayoutTree.width <= 0 || b.LayoutTree.height <= 0 {
 		return "waiting for size information"
 	}
-	lines, err := b.LayoutTree.render(b.ModelMap)
+	pass := &renderPass{
+		modelMap:   b.ModelMap,
+		topology:   b.LayoutTree.topology(look{border: b.border(), style: b.SeparatorStyle}),
+		focusStyle: b.FocusStyle,
+		measure:    b.measure(),
+	}
+	if b.LocalErrors {
+		pass.errorView = b.ErrorView
+		if pass.errorView == nil {
+			pass.errorView = DefaultErrorView
+		}
+	}
+	if focused := b.LayoutTree.at(b.LayoutTree.pathTo(b.focus)); b.focus != "" && focused != nil {
+		inner := focused.ContentRect()
+		pass.focused = &inner
+	}
+	lines, err := b.LayoutTree.render(pass)
 	if err != nil {
 		return err.Error()
 	}
 	return strings.Join(lines, NEWLINE)
 }
 
-// render recursively renders the layout tree with the models contained in ModelMap
-func (n *Node) render(modelMap map[string]tea.Model) ([]string, error) {
+// render recursively renders the layout tree with the models contained in ModelMap.
+// The returned lines fill the whole area of the node, so that the parents do not have to measure them again.
+func (n *Node) render(pass *renderPass) ([]string, error) {
+	lines, err := n.renderContent(pass)
+	if err != nil || n.Frame == nil {
+		return lines, err
+	}
+	return n.renderFrame(pass, lines), nil
+}
+
+// renderContent renders the children or the Model of the node without the frame.
+func (n *Node) renderContent(pass *renderPass) ([]string, error) {
 	if n.address != "" {
 		// is leaf
-		v, ok := modelMap[n.address]
-		if !ok {
-			return nil, fmt.Errorf("model for leaf with address: '%s' not found", n.address)
+		leaf, err := n.renderLeaf(pass)
+		if err != nil && pass.errorView != nil {
+			return n.renderError(pass, err), nil
 		}
-		leaf := strings.Split(v.View(), NEWLINE)
-		if len(leaf) > n.height {
-			return leaf, fmt.Errorf("expecting less or equal to %d lines, but the Model with address '%s' has returned to much lines: %d", n.height, n.address, len(leaf))
-		}
-		for _, line := range leaf {
-			if lineWidth := ansi.PrintableRuneWidth(line); lineWidth > n.width {
-				return leaf, fmt.Errorf("expecting less or equal to %d character width of all lines, but the Model with address '%s' has returned a to long line with %d characters:%s'%s'", n.width, n.address, lineWidth, NEWLINE, line)
-			}
-		}
-		return leaf, nil
+		return leaf, err
 	}
 
 	// is node
+	if n.Grid != nil {
+		return n.renderGrid(pass)
+	}
 	if n.VerticalStacked {
-		return n.renderVertical(modelMap)
+		return n.renderVertical(pass)
 	}
-	return n.renderHorizontal(modelMap)
+	return n.renderHorizontal(pass)
 }
 
-func (n *Node) renderVertical(modelMap map[string]tea.Model) ([]string, error) {
-	if len(n.Children) == 0 {
-		return nil, fmt.Errorf("no children to render - this node should be a leaf (see CreateLeaf) or it should not exist")
+// renderLeaf renders the Model of the leaf and pads its lines to the size of the leaf.
+func (n *Node) renderLeaf(pass *renderPass) ([]string, error) {
+	v, ok := pass.modelMap[n.address]
+	if !ok {
+		return nil, &Error{Kind: KindNotFound, Address: n.address, Err: fmt.Errorf("model for leaf with address: '%s' not found", n.address)}
 	}
+	inner := n.ContentRect()
+	leaf, err := n.fit(pass.measure, strings.Split(v.View(), NEWLINE), inner.Width, inner.Height)
+	if err != nil {
+		return leaf, err
+	}
+	leaf = isolate(leaf)
+	if n.Background != nil {
+		return fill(pass.measure, leaf, inner.Width, inner.Height, n.Background), nil
+	}
+	return pass.measure.pad(leaf, inner.Width, inner.Height), nil
+}
 
-	boxes := make([]string, 0, n.height)
-
-	targetWidth := n.Children[0].width
+func (n *Node) renderVertical(pass *renderPass) ([]string, error) {
+	if len(n.Children) == 0 {
+		return nil, newError(KindInvalid, "no children to render - this node should be a leaf (see CreateLeaf) or it should not exist")
+	}
 
+	inner := n.ContentRect()
+	boxes := make([]string, 0, inner.Height)
 	for i, child := range n.Children {
-		if child.width != targetWidth {
-			return nil, fmt.Errorf("inconsistent size information: all children should have the same width when vertical arranged but did not")
+		if child.width != inner.Width {
+			return nil, childError(i, newError(KindInvalid, "inconsistent size information: all children should have the same width when vertical arranged but did not"))
 		}
-		lines, err := child.render(modelMap)
+		lines, err := child.render(pass)
 		if err != nil {
-			return lines, wrapError(i, n.VerticalStacked, err)
-		}
-		if len(lines) > child.height {
-			err := fmt.Errorf("model has too much lines: %d, when it should have at most %d", len(lines), child.height)
-			return lines, wrapError(i, n.VerticalStacked, err)
+			return lines, childError(i, err)
 		}
 		if !n.noBorder && i > 0 {
-			lines = append([]string{strings.Repeat(VerticalSeparator, targetWidth)}, lines...)
-		}
-		// check for too wide lines and because we are on it, pad them to correct width.
-		for i, line := range lines {
-			lineWidth := ansi.PrintableRuneWidth(line)
-			if lineWidth > targetWidth {
-				err := fmt.Errorf("to long line: %s", line)
-				return lines, wrapError(i, n.VerticalStacked, err)
-			}
-			lines[i] = fmt.Sprintf("%s%s", line, strings.Repeat(SPACE, targetWidth-lineWidth))
+			boxes = append(boxes, pass.horizontalLine(child.x, child.y-1, inner.Width))
 		}
 		boxes = append(boxes, lines...)
-		// add more lines to boxes to match the Height of the child-box
-		for c := 0; c < child.height-len(lines); c++ {
-			boxes = append(boxes, strings.Repeat(SPACE, targetWidth))
-		}
+	}
+	// fill the space which is left empty by the children
+	for len(boxes) < inner.Height {
+		boxes = append(boxes, strings.Repeat(SPACE, inner.Width))
 	}
 	return boxes, nil
-
 }
-func (n *Node) renderHorizontal(modelMap map[string]tea.Model) ([]string, error) {
+
+func (n *Node) renderHorizontal(pass *renderPass) ([]string, error) {
 	if len(n.Children) == 0 {
-		return nil, fmt.Errorf("no children to render - this node should be a leaf or should not exist")
+		return nil, newError(KindInvalid, "no children to render - this node should be a leaf or should not exist")
 	}
-	//            y  x
-	var joinedStr [][]string
-	targetHeigth := n.Children[0].height
 
-	// bring all to same height if they are smaller then there own size
-	for i, boxer := range n.Children {
-		if targetHeigth != boxer.height {
-			err := fmt.Errorf("inconsistent size information: all children should have the same height when horizontal arranged but did not")
-			return nil, wrapError(i, n.VerticalStacked, err)
+	inner := n.ContentRect()
+	children := make([][]string, len(n.Children))
+	used := n.borderWidth() * (len(n.Children) - 1)
+	for i := range n.Children {
+		child := &n.Children[i]
+		if child.height != inner.Height {
+			return nil, childError(i, newError(KindInvalid, "inconsistent size information: all children should have the same height when horizontal arranged but did not"))
 		}
-
-		lines, err := boxer.render(modelMap)
+		lines, err := child.render(pass)
 		if err != nil {
-			return lines, wrapError(i, n.VerticalStacked, err)
+			return lines, childError(i, err)
 		}
+		children[i] = lines
+		used += child.width
+	}
+	// the space which is left empty by the children
+	var rest string
+	if inner.Width > used {
+		rest = strings.Repeat(SPACE, inner.Width-used)
+	}
 
-		if len(lines) > targetHeigth {
-			err := fmt.Errorf("model has too much lines: %d, when it should have at most %d", len(lines), targetHeigth)
-			return lines, wrapError(i, n.VerticalStacked, err)
-		}
-		if len(lines) < targetHeigth {
-			lines = append(lines, make([]string, targetHeigth-len(lines))...)
-		}
-		joinedStr = append(joinedStr, lines)
-	}
-
-	length := len(joinedStr)
-	// Join the horizontal lines together
-	var allStr []string
-	// y
-	for c := 0; c < targetHeigth; c++ {
-		fullLine := make([]string, 0, length)
-		// x
-		for i := 0; i < length; i++ {
-			boxWidth := n.Children[i].width
-			line := joinedStr[i][c]
-			lineWidth := ansi.PrintableRuneWidth(line)
-			if lineWidth > boxWidth {
-				err := fmt.Errorf("model has a too wide line: %s", line)
-				return nil, wrapError(i, n.VerticalStacked, err)
+	lines := make([]string, 0, inner.Height)
+	for y := 0; y < inner.Height; y++ {
+		var line strings.Builder
+		for i := range n.Children {
+			if !n.noBorder && i > 0 {
+				line.WriteString(pass.separator(n.Children[i].x-1, inner.Y+y))
 			}
-			var pad string
-			if lineWidth < boxWidth {
-				pad = strings.Repeat(SPACE, boxWidth-lineWidth)
-			}
-			fullLine = append(fullLine, line+pad)
-		}
-		var border string
-		if !n.noBorder {
-			border = HorizontalSeparator
+			line.WriteString(children[i][y])
 		}
-
-		allStr = append(allStr, strings.Join(fullLine, border))
+		line.WriteString(rest)
+		lines = append(lines, line.String())
 	}
-	return allStr, nil
+	return lines, nil
+}
 
+// UpdateSize set the width and height of all Node's.
+// The commands returned by the Models while they are resized are batched together and returned,
+// even if an error occurred, in which case only the commands gathered till the error are returned.
+// If Constraints are set, the sizes are adjusted so that they satisfy them.
+func (b *Boxer) UpdateSize(size tea.WindowSizeMsg) (tea.Cmd, error) {
+	b.lastSize = size
+	b.LayoutTree.x, b.LayoutTree.y = 0, 0
+	pass := &sizePass{modelMap: b.ModelMap}
+	if len(b.Constraints) > 0 {
+		// the sizes without the constraints are the preferred sizes while solving the constraints
+		dry := &sizePass{modelMap: b.ModelMap, dry: true}
+		if err := b.LayoutTree.updateSize(size, dry); err != nil {
+			return nil, err
+		}
+		overrides, err := b.solveConstraints(size)
+		if err != nil {
+			return nil, err
+		}
+		pass.overrides = overrides
+	}
+	err := b.LayoutTree.updateSize(size, pass)
+	return tea.Batch(pass.cmds...), err
 }
 
-// UpdateSize set the width and height of all Node's
-func (b *Boxer) UpdateSize(size tea.WindowSizeMsg) error {
-	return b.LayoutTree.updateSize(size, b.ModelMap)
+// sizePass holds the state which is shared by all nodes while updating the size of the layout-tree.
+type sizePass struct {
+	modelMap map[string]tea.Model
+
+	// cmds collects the commands returned by the Models
+	cmds []tea.Cmd
+
+	// dry is set if only the sizes should be calculated without telling the Models about them
+	dry bool
+
+	// overrides holds the sizes of the children of a node, which are used instead of the SizeFunc or the Sizing
+	overrides map[*Node][]int
 }
 
 // recursive setting of the height and width according to the orientation and the SizeFunc
-// or evenly if no SizeFunc is provided
-func (n *Node) updateSize(size tea.WindowSizeMsg, modelMap map[string]tea.Model) error {
-	// set size before it may be reduced according to the border
+// or the Sizing of the children if no SizeFunc is provided
+func (n *Node) updateSize(size tea.WindowSizeMsg, pass *sizePass) error {
+	// set size before it may be reduced according to the frame and the border
 	n.width, n.height = size.Width, size.Height
 
+	// reduce size by the cells of the frame
+	if f := n.frameWidth(); f > 0 {
+		size.Width -= 2 * f
+		size.Height -= 2 * f
+		if size.Width <= 0 || size.Height <= 0 {
+			return &Error{
+				Kind:     KindTooSmall,
+				Address:  n.address,
+				Expected: Size{Width: n.width, Height: n.height},
+				Actual:   n.neededSize(),
+				Err:      fmt.Errorf("not enough space for the frame and the content within it"),
+			}
+		}
+	}
+
+	if n.Grid != nil {
+		return n.updateGridSize(size, pass)
+	}
+
 	// reduce size for children if border is set
 	if !n.noBorder {
 		length := len(n.Children)
 		if length == 0 {
-			return fmt.Errorf("the border attribute should not be set on a leaf or a node without children")
+			return newError(KindInvalid, "the border attribute should not be set on a leaf or a node without children")
 		}
 		// subtract the space which is used by the border between the children
 		if n.VerticalStacked {
@@ -258,128 +443,130 @@ func (n *Node) updateSize(size tea.WindowSizeMsg, modelMap map[string]tea.Model)
 		// this returns a error since it is expected that the size might change to to small
 		// and return this as a error makes it clear that it is also expected that the calling code has to change the layout
 		// according to the size-change or display an alternative message till the size is big enough again.
-		return SizeError(fmt.Errorf("not enough space for at least one node or leaf in the Layout-tree"))
+		// the needed size includes the frame, the border and the minimal sizes of all descendants
+		return &Error{
+			Kind:     KindTooSmall,
+			Address:  n.address,
+			Expected: Size{Width: n.width, Height: n.height},
+			Actual:   n.neededSize(),
+			Err:      fmt.Errorf("not enough space for at least one node or leaf in the Layout-tree"),
+		}
 	}
 
 	if n.address != "" {
 		// is leaf
 		if len(n.Children) != 0 {
-			return fmt.Errorf("a leaf should not have Children")
+			return &Error{Kind: KindInvalid, Address: n.address, Err: fmt.Errorf("a leaf should not have Children")}
 		}
 
-		v, ok := modelMap[n.address]
+		v, ok := pass.modelMap[n.address]
 		if !ok {
-			return fmt.Errorf("no model with address '%s' found", n.address)
+			return &Error{Kind: KindNotFound, Address: n.address, Err: fmt.Errorf("no model with address '%s' found", n.address)}
+		}
+		if pass.dry {
+			return nil
 		}
 		// tell model its size
-		v, _ = v.Update(tea.WindowSizeMsg{Width: size.Width, Height: size.Height})
-		modelMap[n.address] = v
+		v, cmd := v.Update(tea.WindowSizeMsg{Width: size.Width, Height: size.Height})
+		pass.modelMap[n.address] = v
+		pass.cmds = append(pass.cmds, wrapCmd(n.address, cmd))
 		return nil
 	}
 
 	// is node
 
-	if n.SizeFunc == nil {
-
-		// share space evenly
+	length := len(n.Children)
+	if length == 0 {
+		return newError(KindInvalid, "no children to render - this node should be a leaf or should not exist")
+	}
+	available := size.Width
+	if n.VerticalStacked {
+		available = size.Height
+	}
 
-		length := len(n.Children)
-		if length == 0 {
-			return fmt.Errorf("no children to render - this node should be a leaf or should not exist")
+	sizeList, ok := pass.overrides[n]
+	if ok {
+		if len(sizeList) != length {
+			return newError(KindInvalid, "got %d sizes to override but want one for each child and thus: %d", len(sizeList), length)
 		}
-		width := size.Width / length
-		height := size.Height
-
-		// hold division remainder (rest)
-		restWidth := size.Width % length
-		var restHeight int
-
-		if n.VerticalStacked {
-			width = size.Width
-			height = size.Height / length
-
-			restHeight = size.Height % length
-			restWidth = 0
+	} else if len(n.Sizes) == length {
+		// the sizes were set by dragging a separator
+		var err error
+		sizeList, err = n.scaledSizes(available)
+		if err != nil {
+			return n.tooSmall(err)
 		}
-
+	} else if n.SizeFunc != nil {
+		// has SizeFunc so split the space according to it
+		sizeList = n.SizeFunc(*n, available)
+		if len(sizeList) != length {
+			return newError(KindInvalid, "SizeFunc returned %d WindowSizeMsg's but want one for each child and thus: %d", len(sizeList), length)
+		}
+	} else {
+		// split the space according to the Sizing of the children, which is evenly if none is set
+		specs := make([]Sizing, length)
 		for i, c := range n.Children {
-			var tmpWidth, tmpHeight int
-			if restWidth > 0 {
-				tmpWidth = 1
-				restWidth--
-			}
-			if restHeight > 0 {
-				tmpHeight = 1
-				restHeight--
-			}
-
-			err := c.updateSize(
-				tea.WindowSizeMsg{
-					Width:  width + tmpWidth,
-					Height: height + tmpHeight,
-				},
-				modelMap,
-			)
-			if err != nil {
-				layout := "horizontal"
-				if n.VerticalStacked {
-					layout = "vertical"
-				}
-				return fmt.Errorf("Error while updating the %d child in %s layout: %w", i, layout, err)
-			}
-			n.Children[i] = c
+			specs[i] = c.Sizing
+		}
+		var err error
+		sizeList, err = resolveSizes(specs, available)
+		if err != nil {
+			return n.tooSmall(err)
 		}
-		return nil
 	}
 
-	// has SizeFunc so split the space according to it
-	var sizeList []int
-	if n.VerticalStacked {
-		sizeList = n.SizeFunc(*n, size.Height)
-	} else {
-		sizeList = n.SizeFunc(*n, size.Width)
-	}
-	if len(sizeList) != len(n.Children) {
-		return fmt.Errorf("SizeFunc returned %d WindowSizeMsg's but want one for each child and thus: %d", len(sizeList), len(n.Children))
-	}
-	var heightSum, widthSum int
-	for i, c := range n.Children {
+	var sum, offset int
+	for i := range n.Children {
+		c := &n.Children[i]
 		// set fixed dimension
 		s := size
 
-		// change variable dimension according to orientation and the SizeFunc
+		// change variable dimension according to orientation and the sizeList
 		if n.VerticalStacked {
 			s.Height = sizeList[i]
 		} else {
 			s.Width = sizeList[i]
 		}
 
-		err := c.updateSize(s, modelMap)
+		c.x, c.y = n.childPosition(offset)
+		offset += sizeList[i] + n.borderWidth()
+
+		err := c.updateSize(s, pass)
 		if err != nil {
-			layout := "horizontal"
-			if n.VerticalStacked {
-				layout = "vertical"
-			}
-			return fmt.Errorf("Error while updating the %d child in %s layout: %w", i, layout, err)
+			return childError(i, err)
 		}
-		n.Children[i] = c
 
 		// check sanity
+		sum += sizeList[i]
+	}
+
+	// the sum of the children size can not be bigger what the parent provided
+	if sum > available {
+		dimension := "width"
 		if n.VerticalStacked {
-			heightSum += s.Height
-			continue
+			dimension = "height"
 		}
-		widthSum += s.Width
+		return newError(KindInvalid, "SizeFunc spread more %s than it can", dimension)
 	}
+	return nil
+}
 
-	// the sum of the children size can not be bigger what the parent provided
-	if n.VerticalStacked && heightSum > size.Height {
-		return fmt.Errorf("SizeFunc spread more height than it can")
+// childPosition returns the absolute position of a child which is offset cells away from the start of the inner area of this node
+// along the orientation of this node.
+func (n *Node) childPosition(offset int) (int, int) {
+	inner := n.ContentRect()
+	if n.VerticalStacked {
+		return inner.X, inner.Y + offset
 	}
-	if widthSum > size.Width {
-		return fmt.Errorf("SizeFunc spread more width than it can")
+	return inner.X + offset, inner.Y
+}
+
+// borderWidth returns how many cells the separator between two children uses.
+func (n *Node) borderWidth() int {
+	if n.noBorder {
+		return 0
 	}
-	return nil
+	return 1
 }
 
 // CreateLeaf is the only way to create a Node which is treated as a Leaf in the layout-tree.
@@ -393,7 +580,12 @@ func (b *Boxer) CreateLeaf(address string, model tea.Model) (Node, error) {
 	if b.ModelMap == nil {
 		b.ModelMap = make(map[string]tea.Model)
 	}
+	if b.initialized == nil {
+		b.initialized = make(map[string]bool)
+	}
 	b.ModelMap[address] = model
+	// the new Model is initialized by the next call of Init or Update
+	delete(b.initialized, address)
 	return Node{
 		address:  address,
 		noBorder: true,
@@ -406,7 +598,7 @@ func (b *Boxer) CreateLeaf(address string, model tea.Model) (Node, error) {
 func (b *Boxer) EditLeaf(address string, editFunc func(tea.Model) (tea.Model, error)) error {
 	model, ok := b.ModelMap[address]
 	if !ok {
-		return NotFoundError(fmt.Errorf("address '%s' not found", address))
+		return &Error{Kind: KindNotFound, Address: address, Err: fmt.Errorf("address '%s' not found", address)}
 	}
 
 	model, err := editFunc(model)
@@ -444,12 +636,3 @@ func (n *Node) GetHeight() int { return n.height }
 func CreateNoBorderNode() Node {
 	return Node{noBorder: true}
 }
-
-func wrapError(index int, vertical bool, toWrap error) error {
-	index++
-	layout := "horizontal"
-	if vertical {
-		layout = "vertical"
-	}
-	return fmt.Errorf("while rendering the %d child of a %s node a error occured:\n%w", index, layout, toWrap)
-}
@@ -1,6 +1,7 @@
 package bubbleboxer
 
 import (
+	"reflect"
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -75,7 +76,7 @@ func TestRenderValidTree(t *testing.T) {
 			},
 		},
 	}
-	err := b.UpdateSize(tea.WindowSizeMsg{Width: 17, Height: 22})
+	_, err := b.UpdateSize(tea.WindowSizeMsg{Width: 17, Height: 22})
 	if err != nil {
 		t.Error(err)
 	}
@@ -108,3 +109,94 @@ func TestMsgHandling(t *testing.T) {
 
 	b.Update(tea.WindowSizeMsg{Width: 17, Height: 17})
 }
+
+// sizeModel returns a command on every resize which reports the new size
+type sizeModel struct{}
+
+func (s sizeModel) Init() tea.Cmd { return nil }
+func (s sizeModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if size, ok := msg.(tea.WindowSizeMsg); ok {
+		return s, func() tea.Msg { return size }
+	}
+	return s, nil
+}
+func (s sizeModel) View() string { return "" }
+
+func TestResizeCommands(t *testing.T) {
+	b := Boxer{}
+	b.LayoutTree = Node{
+		SizeFunc: func(_ Node, widthOrHeight int) []int {
+			return []int{2, widthOrHeight - 3}
+		},
+		Children: []Node{
+			stripErr(b.CreateLeaf("a", sizeModel{})),
+			stripErr(b.CreateLeaf("b", sizeModel{})),
+		},
+	}
+
+	cmd, err := b.UpdateSize(tea.WindowSizeMsg{Width: 10, Height: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msgs := collectMsgs(cmd); len(msgs) != 2 {
+		t.Errorf("expected the commands of both leaves but got %d messages", len(msgs))
+	}
+
+	// the second leaf gets no width and thus fails, but the command of the first leaf is still returned
+	cmd, err = b.UpdateSize(tea.WindowSizeMsg{Width: 3, Height: 3})
+	if err == nil {
+		t.Fatal("expected an error since the second leaf has no space left")
+	}
+	msgs := collectMsgs(cmd)
+	if len(msgs) != 1 || msgs[0] != (AddressedMsg{Address: "a", Msg: tea.WindowSizeMsg{Width: 2, Height: 3}}) {
+		t.Errorf("expected the command of the first leaf to be returned but got: %v", msgs)
+	}
+}
+
+// collectMsgs executes the command and all commands batched within and returns the resulting messages.
+func collectMsgs(cmd tea.Cmd) []tea.Msg {
+	if cmd == nil {
+		return nil
+	}
+	msg := cmd()
+	v := reflect.ValueOf(msg)
+	if v.Kind() == reflect.Slice && v.Type().Elem() == reflect.TypeOf(tea.Cmd(nil)) {
+		var msgs []tea.Msg
+		for i := 0; i < v.Len(); i++ {
+			msgs = append(msgs, collectMsgs(v.Index(i).Interface().(tea.Cmd))...)
+		}
+		return msgs
+	}
+	return []tea.Msg{msg}
+}
+
+// initModel reports its name when it is initialized
+type initModel string
+
+func (i initModel) Init() tea.Cmd                       { return func() tea.Msg { return string(i) } }
+func (i initModel) Update(tea.Msg) (tea.Model, tea.Cmd) { return i, nil }
+func (i initModel) View() string                        { return "" }
+
+func TestInit(t *testing.T) {
+	b := Boxer{}
+	b.LayoutTree = Node{
+		Children: []Node{
+			stripErr(b.CreateLeaf("a", initModel("a"))),
+			stripErr(b.CreateLeaf("b", initModel("b"))),
+		},
+	}
+	msgs := collectMsgs(b.Init())
+	if !reflect.DeepEqual(msgs, []tea.Msg{AddressedMsg{"a", "a"}, AddressedMsg{"b", "b"}}) {
+		t.Errorf("Init should initialize all Models but returned: %v", msgs)
+	}
+
+	// a Model added after Init is initialized with the next Update
+	b.LayoutTree.Children = append(b.LayoutTree.Children, stripErr(b.CreateLeaf("c", initModel("c"))))
+	model, cmd := b.Update(nil)
+	if msgs := collectMsgs(cmd); !reflect.DeepEqual(msgs, []tea.Msg{AddressedMsg{"c", "c"}}) {
+		t.Errorf("Update should only initialize the new Model but returned: %v", msgs)
+	}
+	if _, cmd := model.Update(nil); cmd != nil {
+		t.Error("the Models should only be initialized once")
+	}
+}
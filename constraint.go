@@ -0,0 +1,542 @@
+package bubbleboxer
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Edge names an edge or a dimension of a node, which can be used in a Constraint.
+type Edge int
+
+// The edges are absolute positions and the dimensions are sizes, all measured in cells.
+const (
+	EdgeLeft Edge = iota
+	EdgeTop
+	EdgeRight
+	EdgeBottom
+	EdgeWidth
+	EdgeHeight
+)
+
+func (e Edge) String() string {
+	switch e {
+	case EdgeLeft:
+		return "left"
+	case EdgeTop:
+		return "top"
+	case EdgeRight:
+		return "right"
+	case EdgeBottom:
+		return "bottom"
+	case EdgeWidth:
+		return "width"
+	case EdgeHeight:
+		return "height"
+	}
+	return fmt.Sprintf("Edge(%d)", int(e))
+}
+
+// Anchor references an Edge of a node in the layout-tree.
+// If Address is set, the Anchor references the first leaf with this address,
+// otherwise the node which is reached by following the children indices of Path from the root.
+type Anchor struct {
+	Address string
+	Path    []int
+	Edge    Edge
+}
+
+func (a Anchor) String() string {
+	if a.Address != "" {
+		return fmt.Sprintf("'%s'.%s", a.Address, a.Edge)
+	}
+	return fmt.Sprintf("%v.%s", a.Path, a.Edge)
+}
+
+// Term is an Anchor multiplied with a Coefficient.
+type Term struct {
+	Anchor      Anchor
+	Coefficient float64
+}
+
+// Expression is the sum of the Terms and the Constant.
+type Expression struct {
+	Terms    []Term
+	Constant float64
+}
+
+// LeafEdge returns an Expression of the edge of the leaf with the address.
+func LeafEdge(address string, edge Edge) Expression {
+	return Expression{Terms: []Term{{Anchor: Anchor{Address: address, Edge: edge}, Coefficient: 1}}}
+}
+
+// NodeEdge returns an Expression of the edge of the node which is reached by following the children indices of path.
+func NodeEdge(path []int, edge Edge) Expression {
+	return Expression{Terms: []Term{{Anchor: Anchor{Path: path, Edge: edge}, Coefficient: 1}}}
+}
+
+// Value returns an Expression of a constant value.
+func Value(constant float64) Expression {
+	return Expression{Constant: constant}
+}
+
+// Plus returns the sum of both Expressions.
+func (e Expression) Plus(other Expression) Expression {
+	terms := make([]Term, 0, len(e.Terms)+len(other.Terms))
+	terms = append(append(terms, e.Terms...), other.Terms...)
+	return Expression{Terms: terms, Constant: e.Constant + other.Constant}
+}
+
+// Minus returns the difference of both Expressions.
+func (e Expression) Minus(other Expression) Expression {
+	return e.Plus(other.Times(-1))
+}
+
+// Times returns the Expression multiplied with the factor.
+func (e Expression) Times(factor float64) Expression {
+	terms := make([]Term, len(e.Terms))
+	for i, t := range e.Terms {
+		terms[i] = Term{Anchor: t.Anchor, Coefficient: t.Coefficient * factor}
+	}
+	return Expression{Terms: terms, Constant: e.Constant * factor}
+}
+
+// Equal returns a required Constraint that both Expressions are equal.
+func (e Expression) Equal(other Expression) Constraint {
+	return Constraint{Left: e, Relation: Equal, Right: other}
+}
+
+// LessOrEqual returns a required Constraint that this Expression is less or equal to the other.
+func (e Expression) LessOrEqual(other Expression) Constraint {
+	return Constraint{Left: e, Relation: LessOrEqual, Right: other}
+}
+
+// GreaterOrEqual returns a required Constraint that this Expression is greater or equal to the other.
+func (e Expression) GreaterOrEqual(other Expression) Constraint {
+	return Constraint{Left: e, Relation: GreaterOrEqual, Right: other}
+}
+
+func (e Expression) String() string {
+	parts := make([]string, 0, len(e.Terms)+1)
+	for _, t := range e.Terms {
+		if t.Coefficient == 1 {
+			parts = append(parts, t.Anchor.String())
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%g*%s", t.Coefficient, t.Anchor))
+	}
+	if e.Constant != 0 || len(parts) == 0 {
+		parts = append(parts, fmt.Sprintf("%g", e.Constant))
+	}
+	return strings.Join(parts, " + ")
+}
+
+// Relation is the relation between the two sides of a Constraint.
+type Relation int
+
+// Relations of a Constraint.
+const (
+	Equal Relation = iota
+	LessOrEqual
+	GreaterOrEqual
+)
+
+func (r Relation) String() string {
+	switch r {
+	case Equal:
+		return "=="
+	case LessOrEqual:
+		return "<="
+	case GreaterOrEqual:
+		return ">="
+	}
+	return fmt.Sprintf("Relation(%d)", int(r))
+}
+
+// Strength defines how important a Constraint is.
+// A Constraint is only violated, if this is needed to satisfy a stronger one.
+type Strength float64
+
+// Strengths of a Constraint. The zero value of a Strength is treated as Required.
+const (
+	Required Strength = strengthRequired
+	Strong   Strength = strengthStrong
+	Medium   Strength = strengthMedium
+	Weak     Strength = strengthWeak
+)
+
+// Constraint is a linear equality or inequality between the edges of the nodes in the layout-tree.
+// Unlike a SizeFunc a Constraint can relate nodes in different branches of the layout-tree.
+type Constraint struct {
+	Left     Expression
+	Relation Relation
+	Right    Expression
+	Strength Strength
+}
+
+// WithStrength returns the Constraint with the given Strength.
+func (c Constraint) WithStrength(strength Strength) Constraint {
+	c.Strength = strength
+	return c
+}
+
+func (c Constraint) String() string {
+	return fmt.Sprintf("%s %s %s", c.Left, c.Relation, c.Right)
+}
+
+func (c Constraint) strength() float64 {
+	if c.Strength <= 0 {
+		return strengthRequired
+	}
+	return clipStrength(float64(c.Strength))
+}
+
+// rootStrength is the strength of the size of the root node, which is stronger than all not required constraints.
+const rootStrength = 999 * strengthStrong
+
+// nodeVariables hold the variables of the solver which describe the rectangle of a node.
+type nodeVariables struct {
+	left, top, width, height *variable
+}
+
+// expression returns the expression of the edge
+func (v *nodeVariables) expression(edge Edge) (expression, error) {
+	single := func(vars ...*variable) expression {
+		e := expression{}
+		for _, v := range vars {
+			e.terms = append(e.terms, term{variable: v, coefficient: 1})
+		}
+		return e
+	}
+	switch edge {
+	case EdgeLeft:
+		return single(v.left), nil
+	case EdgeTop:
+		return single(v.top), nil
+	case EdgeRight:
+		return single(v.left, v.width), nil
+	case EdgeBottom:
+		return single(v.top, v.height), nil
+	case EdgeWidth:
+		return single(v.width), nil
+	case EdgeHeight:
+		return single(v.height), nil
+	}
+	return expression{}, fmt.Errorf("unknown edge: %s", edge)
+}
+
+// along returns the position and the size of the node along the orientation of its parent.
+func (v *nodeVariables) along(vertical bool) (*variable, *variable) {
+	if vertical {
+		return v.top, v.height
+	}
+	return v.left, v.width
+}
+
+// stay is the preferred size of a child, which is suggested to the solver on every update of the size
+type stay struct {
+	path     []int
+	vertical bool
+	variable *variable
+}
+
+// layoutSolver is the solver together with the variables for the current layout-tree and Constraints.
+type layoutSolver struct {
+	// signature describes the layout-tree and Constraints for which the solver was build
+	signature string
+
+	solver *solver
+	nodes  map[string]*nodeVariables
+	stays  []stay
+}
+
+// solveConstraints solves the Constraints and returns the sizes of the children of every node.
+// The sizes calculated beforehand (without the Constraints) are used as preferred sizes.
+func (b *Boxer) solveConstraints(size tea.WindowSizeMsg) (map[*Node][]int, error) {
+	signature := b.constraintSignature()
+	if b.solver == nil || b.solver.signature != signature {
+		ls, err := b.newLayoutSolver(signature)
+		if err != nil {
+			b.solver = nil
+			return nil, err
+		}
+		b.solver = ls
+	}
+	ls := b.solver
+
+	root := ls.nodes[pathKey(nil)]
+	suggestions := []struct {
+		variable *variable
+		value    int
+	}{
+		{root.width, size.Width},
+		{root.height, size.Height},
+	}
+	for _, s := range ls.stays {
+		node := b.LayoutTree.at(s.path)
+		value := node.width
+		if s.vertical {
+			value = node.height
+		}
+		suggestions = append(suggestions, struct {
+			variable *variable
+			value    int
+		}{s.variable, value})
+	}
+	for _, s := range suggestions {
+		if err := ls.solver.suggestValue(s.variable, float64(s.value)); err != nil {
+			b.solver = nil
+			return nil, err
+		}
+	}
+	ls.solver.updateVariables()
+
+	if math.Abs(root.width.value-float64(size.Width)) > 0.5 || math.Abs(root.height.value-float64(size.Height)) > 0.5 {
+		return nil, &Error{
+			Kind:     KindConstraint,
+			Expected: Size{Width: size.Width, Height: size.Height},
+			Actual:   Size{Width: int(math.Round(root.width.value)), Height: int(math.Round(root.height.value))},
+			Err:      fmt.Errorf("the required constraints can not be satisfied with the size %dx%d", size.Width, size.Height),
+		}
+	}
+
+	overrides := make(map[*Node][]int)
+	var collect func(n *Node, path []int)
+	collect = func(n *Node, path []int) {
+		if len(n.Children) == 0 || n.Grid != nil {
+			return
+		}
+		sizes := make([]int, len(n.Children))
+		for i := range n.Children {
+			childPath := append(append([]int{}, path...), i)
+			position, length := ls.nodes[pathKey(childPath)].along(n.VerticalStacked)
+			sizes[i] = int(math.Round(position.value+length.value) - math.Round(position.value))
+			collect(&n.Children[i], childPath)
+		}
+		overrides[n] = sizes
+	}
+	collect(&b.LayoutTree, nil)
+	return overrides, nil
+}
+
+// newLayoutSolver builds a solver with the structure of the layout-tree and the Constraints.
+func (b *Boxer) newLayoutSolver(signature string) (*layoutSolver, error) {
+	ls := &layoutSolver{
+		signature: signature,
+		solver:    newSolver(),
+		nodes:     make(map[string]*nodeVariables),
+	}
+
+	// add adds a constraint of the structure, the first error is kept and stops adding further constraints
+	var err error
+	add := func(e expression, r relation, strength float64) {
+		if err != nil {
+			return
+		}
+		err = ls.solver.addConstraint(&constraint{expression: e, relation: r, strength: strength})
+	}
+	diff := func(terms ...term) expression {
+		return expression{terms: terms}
+	}
+
+	var build func(n *Node, path []int) *nodeVariables
+	build = func(n *Node, path []int) *nodeVariables {
+		key := pathKey(path)
+		vars := &nodeVariables{
+			left:   &variable{name: key + ".left"},
+			top:    &variable{name: key + ".top"},
+			width:  &variable{name: key + ".width"},
+			height: &variable{name: key + ".height"},
+		}
+		ls.nodes[key] = vars
+		add(expression{terms: []term{{vars.width, 1}}, constant: -1}, relationGreaterOrEqual, strengthRequired)
+		add(expression{terms: []term{{vars.height, 1}}, constant: -1}, relationGreaterOrEqual, strengthRequired)
+
+		if n.Grid != nil {
+			// the children of a grid are sized by the tracks of the grid and can not be constrained
+			return vars
+		}
+		frame := float64(n.frameWidth())
+		var previous *nodeVariables
+		for i := range n.Children {
+			c := &n.Children[i]
+			childPath := append(append([]int{}, path...), i)
+			child := build(c, childPath)
+
+			position, length := child.along(n.VerticalStacked)
+			parentPosition, _ := vars.along(n.VerticalStacked)
+			crossPosition, crossLength := child.along(!n.VerticalStacked)
+			parentCrossPosition, parentCrossLength := vars.along(!n.VerticalStacked)
+
+			// the children fill the parent (within its frame) across the orientation
+			cross := diff(term{crossPosition, 1}, term{parentCrossPosition, -1})
+			cross.constant = -frame
+			add(cross, relationEqual, strengthRequired)
+			crossFill := diff(term{crossLength, 1}, term{parentCrossLength, -1})
+			crossFill.constant = 2 * frame
+			add(crossFill, relationEqual, strengthRequired)
+
+			// and are placed one after the other along the orientation
+			if previous == nil {
+				first := diff(term{position, 1}, term{parentPosition, -1})
+				first.constant = -frame
+				add(first, relationEqual, strengthRequired)
+			} else {
+				previousPosition, previousLength := previous.along(n.VerticalStacked)
+				e := diff(term{position, 1}, term{previousPosition, -1}, term{previousLength, -1})
+				e.constant = -float64(n.borderWidth())
+				add(e, relationEqual, strengthRequired)
+			}
+			previous = child
+
+			if c.Sizing.Min > 0 {
+				add(expression{terms: []term{{length, 1}}, constant: -float64(c.Sizing.Min)}, relationGreaterOrEqual, strengthStrong)
+			}
+			if c.Sizing.Max > 0 {
+				add(expression{terms: []term{{length, 1}}, constant: -float64(c.Sizing.Max)}, relationLessOrEqual, strengthStrong)
+			}
+
+			if err == nil {
+				err = ls.solver.addEditVariable(length, strengthWeak)
+			}
+			ls.stays = append(ls.stays, stay{path: childPath, vertical: n.VerticalStacked, variable: length})
+		}
+
+		if previous != nil {
+			// the children can not use more space than there parent
+			// and should fill it, if they did so without the constraints
+			position, length := previous.along(n.VerticalStacked)
+			parentPosition, parentLength := vars.along(n.VerticalStacked)
+			end := diff(term{position, 1}, term{length, 1}, term{parentPosition, -1}, term{parentLength, -1})
+			end.constant = frame
+			add(end, relationLessOrEqual, strengthRequired)
+			if n.childrenFill() {
+				add(end, relationEqual, strengthMedium)
+			}
+		}
+		return vars
+	}
+	root := build(&b.LayoutTree, nil)
+	add(diff(term{root.left, 1}), relationEqual, strengthRequired)
+	add(diff(term{root.top, 1}), relationEqual, strengthRequired)
+	if err == nil {
+		err = ls.solver.addEditVariable(root.width, rootStrength)
+	}
+	if err == nil {
+		err = ls.solver.addEditVariable(root.height, rootStrength)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("the structure of the layout-tree can not be solved: %w", err)
+	}
+
+	for i, c := range b.Constraints {
+		left, err := ls.expression(&b.LayoutTree, c.Left)
+		if err != nil {
+			return nil, err
+		}
+		right, err := ls.expression(&b.LayoutTree, c.Right)
+		if err != nil {
+			return nil, err
+		}
+		e := expression{
+			terms:    append(left.terms, negate(right.terms)...),
+			constant: left.constant - right.constant,
+		}
+		var r relation
+		switch c.Relation {
+		case Equal:
+			r = relationEqual
+		case LessOrEqual:
+			r = relationLessOrEqual
+		case GreaterOrEqual:
+			r = relationGreaterOrEqual
+		default:
+			return nil, fmt.Errorf("constraint %d '%s' has a unknown relation", i, c)
+		}
+		if err := ls.solver.addConstraint(&constraint{expression: e, relation: r, strength: c.strength()}); err != nil {
+			return nil, &Error{Kind: KindConstraint, Err: fmt.Errorf("constraint %d '%s' can not be satisfied: %w", i, c, err)}
+		}
+	}
+	return ls, nil
+}
+
+// expression converts the Expression of the Constraint into an expression of the solver.
+func (ls *layoutSolver) expression(root *Node, e Expression) (expression, error) {
+	result := expression{constant: e.Constant}
+	for _, t := range e.Terms {
+		path := t.Anchor.Path
+		if t.Anchor.Address != "" {
+			path = root.pathTo(t.Anchor.Address)
+			if path == nil {
+				return result, &Error{Kind: KindNotFound, Address: t.Anchor.Address, Err: fmt.Errorf("address '%s' of constraint not found", t.Anchor.Address)}
+			}
+		}
+		vars, ok := ls.nodes[pathKey(path)]
+		if !ok {
+			return result, &Error{Kind: KindNotFound, Path: path, Err: fmt.Errorf("path %v of constraint not found or within a grid", path)}
+		}
+		edge, err := vars.expression(t.Anchor.Edge)
+		if err != nil {
+			return result, err
+		}
+		for _, et := range edge.terms {
+			result.terms = append(result.terms, term{variable: et.variable, coefficient: et.coefficient * t.Coefficient})
+		}
+	}
+	return result, nil
+}
+
+func negate(terms []term) []term {
+	negated := make([]term, len(terms))
+	for i, t := range terms {
+		negated[i] = term{variable: t.variable, coefficient: -t.coefficient}
+	}
+	return negated
+}
+
+// constraintSignature describes the structure of the layout-tree and the Constraints,
+// if it changes the solver has to be rebuild.
+func (b *Boxer) constraintSignature() string {
+	var builder strings.Builder
+	var describe func(n *Node, path []int)
+	describe = func(n *Node, path []int) {
+		fmt.Fprintf(&builder, "%v:%t:%t:%t:%d:%q:%d:%t:%d:%d;", path, n.VerticalStacked, n.Grid != nil, n.noBorder, n.frameWidth(), n.address, len(n.Children), n.childrenFill(), n.Sizing.Min, n.Sizing.Max)
+		if n.Grid != nil {
+			return
+		}
+		for i := range n.Children {
+			describe(&n.Children[i], append(append([]int{}, path...), i))
+		}
+	}
+	describe(&b.LayoutTree, nil)
+	for _, c := range b.Constraints {
+		fmt.Fprintf(&builder, "%s:%g;", c, c.strength())
+	}
+	return builder.String()
+}
+
+// childrenFill returns if the children (and the separators between them) use all the space of this node.
+func (n *Node) childrenFill() bool {
+	if len(n.Children) == 0 {
+		return false
+	}
+	sum := n.borderWidth() * (len(n.Children) - 1)
+	for _, c := range n.Children {
+		if n.VerticalStacked {
+			sum += c.height
+			continue
+		}
+		sum += c.width
+	}
+	inner := n.ContentRect()
+	if n.VerticalStacked {
+		return sum == inner.Height
+	}
+	return sum == inner.Width
+}
+
+func pathKey(path []int) string {
+	return fmt.Sprint(path)
+}
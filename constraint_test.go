@@ -0,0 +1,73 @@
+package bubbleboxer
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestConstraints(t *testing.T) {
+	b := Boxer{}
+	b.LayoutTree = Node{
+		VerticalStacked: true,
+		Children: []Node{
+			{
+				Children: []Node{
+					stripErr(b.CreateLeaf("top-left", testModel(""))),
+					stripErr(b.CreateLeaf("top-right", testModel(""))),
+				},
+			},
+			{
+				Children: []Node{
+					stripErr(b.CreateLeaf("sidebar", sizeModel{})),
+					stripErr(b.CreateLeaf("main", testModel(""))),
+				},
+			},
+		},
+	}
+	b.Constraints = []Constraint{
+		LeafEdge("sidebar", EdgeWidth).Equal(Value(10)),
+		// the relation spans two branches of the layout-tree
+		LeafEdge("top-left", EdgeWidth).Equal(LeafEdge("sidebar", EdgeWidth)),
+	}
+
+	for _, width := range []int{41, 30, 60} {
+		cmd, err := b.UpdateSize(tea.WindowSizeMsg{Width: width, Height: 10})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, address := range []string{"top-left", "sidebar"} {
+			node := b.LayoutTree.at(b.LayoutTree.pathTo(address))
+			if node.GetWidth() != 10 {
+				t.Errorf("with a width of %d the leaf '%s' should be 10 wide but is %d", width, address, node.GetWidth())
+			}
+		}
+		for _, row := range b.LayoutTree.Children {
+			if !row.childrenFill() {
+				t.Errorf("with a width of %d the children should still fill their parent", width)
+			}
+		}
+		// the Models are only told about the final size
+		want := AddressedMsg{Address: "sidebar", Msg: tea.WindowSizeMsg{Width: 10, Height: 4}}
+		if msgs := collectMsgs(cmd); len(msgs) != 1 || msgs[0] != want {
+			t.Errorf("expected only the final size to be send but got: %v", msgs)
+		}
+	}
+
+	// the root can not be smaller than the required width
+	b.Constraints = append(b.Constraints, LeafEdge("main", EdgeWidth).GreaterOrEqual(Value(50)))
+	if _, err := b.UpdateSize(tea.WindowSizeMsg{Width: 40, Height: 10}); err == nil {
+		t.Error("expected an error since the required constraints can not be satisfied")
+	}
+
+	// a weak constraint is ignored if it conflicts
+	b.Constraints[2] = b.Constraints[2].WithStrength(Weak)
+	if _, err := b.UpdateSize(tea.WindowSizeMsg{Width: 40, Height: 10}); err != nil {
+		t.Error(err)
+	}
+
+	b.Constraints = []Constraint{LeafEdge("missing", EdgeWidth).Equal(Value(1))}
+	if _, err := b.UpdateSize(tea.WindowSizeMsg{Width: 40, Height: 10}); err == nil {
+		t.Error("expected an error since the constraint references a missing leaf")
+	}
+}
@@ -0,0 +1,223 @@
+package bubbleboxer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// SyntaxError is the underlying error of a Error returned for a layout description which can not be parsed.
+type SyntaxError struct {
+	// Source is the parsed layout description.
+	Source string
+	// Column is the position (starting with 1) of the character in the Source where the error occurred.
+	Column int
+	Msg    string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("syntax error at column %d: %s", e.Column, e.Msg)
+}
+
+// ParseLayout parses the compact description of a layout, for example:
+//
+//	v(header:1, h(left:20%, main, right:30), footer:1)
+//
+// A node is written as 'v' (vertical stacked) or 'h' (side by side) followed by its children in parentheses.
+// All other names are the addresses of leaves, each can only be used once. Both can be followed by a ':' and a Sizing:
+// a whole number are fixed cells, a number up to 100 followed by '%' is a percentage and followed by 'fr' a weight.
+// Without a Sizing the space is shared evenly (see Sizing).
+func ParseLayout(source string) (Layout, error) {
+	p := parser{source: []rune(source)}
+	layout, err := p.layout()
+	if err != nil {
+		return Layout{}, err
+	}
+	p.skipSpace()
+	if p.pos < len(p.source) {
+		return Layout{}, p.fail(p.pos, "unexpected '%c' after the end of the layout", p.source[p.pos])
+	}
+	return layout, nil
+}
+
+// Parse parses the compact description of a layout (see ParseLayout) into a node.
+// The leaves are bound to the Models of the map by there addresses, which are added to the ModelMap as with CreateLeaf
+// and must not be used in the LayoutTree yet.
+// Nothing is added if a error is returned.
+func (b *Boxer) Parse(source string, models map[string]tea.Model) (Node, error) {
+	layout, err := ParseLayout(source)
+	if err != nil {
+		return Node{}, err
+	}
+	registry := make(Registry, len(models))
+	for address, model := range models {
+		model := model
+		registry[address] = func(string) (tea.Model, error) { return model, nil }
+	}
+	built := Boxer{}
+	node, err := built.build(layout, registry)
+	if err != nil {
+		return Node{}, err
+	}
+	for address := range built.ModelMap {
+		if b.LayoutTree.contains(address) {
+			return Node{}, &Error{Kind: KindInvalid, Address: address, Err: fmt.Errorf("the address '%s' is already used in the Layout-tree", address)}
+		}
+	}
+	for address, model := range built.ModelMap {
+		if _, err := b.CreateLeaf(address, model); err != nil {
+			return Node{}, err
+		}
+	}
+	return node, nil
+}
+
+// parser reads a layout description, pos is the index of the next rune.
+type parser struct {
+	source []rune
+	pos    int
+	// addresses holds the addresses of the leaves read so far
+	addresses map[string]bool
+}
+
+// fail returns a syntax error for the rune at the index.
+func (p *parser) fail(index int, format string, a ...interface{}) error {
+	return &Error{Kind: KindInvalid, Err: &SyntaxError{
+		Source: string(p.source),
+		Column: index + 1,
+		Msg:    fmt.Sprintf(format, a...),
+	}}
+}
+
+// found describes the next rune for a error message.
+func (p *parser) found() string {
+	if p.pos >= len(p.source) {
+		return "the end"
+	}
+	return fmt.Sprintf("'%c'", p.source[p.pos])
+}
+
+func (p *parser) skipSpace() {
+	for p.pos < len(p.source) && unicode.IsSpace(p.source[p.pos]) {
+		p.pos++
+	}
+}
+
+// next skips the spaces and returns the next rune or zero at the end.
+func (p *parser) next() rune {
+	p.skipSpace()
+	if p.pos >= len(p.source) {
+		return 0
+	}
+	return p.source[p.pos]
+}
+
+// name reads the name of a node or the address of a leaf.
+func (p *parser) name() string {
+	start := p.pos
+	for p.pos < len(p.source) {
+		r := p.source[p.pos]
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune("_-.", r) {
+			break
+		}
+		p.pos++
+	}
+	return string(p.source[start:p.pos])
+}
+
+// layout reads a node or a leaf with its optional Sizing.
+func (p *parser) layout() (Layout, error) {
+	p.skipSpace()
+	start := p.pos
+	name := p.name()
+	if name == "" {
+		return Layout{}, p.fail(p.pos, "expected a address or a node but got %s", p.found())
+	}
+
+	layout := Layout{Address: name}
+	if p.next() != '(' {
+		if p.addresses[name] {
+			return Layout{}, p.fail(start, "the address '%s' is used more than once", name)
+		}
+		if p.addresses == nil {
+			p.addresses = make(map[string]bool)
+		}
+		p.addresses[name] = true
+	} else {
+		switch name {
+		case "v":
+			layout = Layout{Vertical: true}
+		case "h":
+			layout = Layout{}
+		default:
+			return Layout{}, p.fail(start, "unknown node '%s', expected 'v' or 'h'", name)
+		}
+		p.pos++
+		for {
+			child, err := p.layout()
+			if err != nil {
+				return Layout{}, err
+			}
+			layout.Children = append(layout.Children, child)
+			r := p.next()
+			if r == ')' {
+				p.pos++
+				break
+			}
+			if r != ',' {
+				return Layout{}, p.fail(p.pos, "expected ',' or ')' but got %s", p.found())
+			}
+			p.pos++
+		}
+	}
+
+	if p.next() != ':' {
+		return layout, nil
+	}
+	p.pos++
+	sizing, err := p.sizing()
+	if err != nil {
+		return Layout{}, err
+	}
+	layout.Sizing = sizing
+	return layout, nil
+}
+
+// sizing reads a number of cells, a percentage or a weight.
+func (p *parser) sizing() (Sizing, error) {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.source) && (unicode.IsDigit(p.source[p.pos]) || p.source[p.pos] == '.') {
+		p.pos++
+	}
+	number := string(p.source[start:p.pos])
+	if number == "" {
+		return Sizing{}, p.fail(p.pos, "expected a size but got %s", p.found())
+	}
+	value, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return Sizing{}, p.fail(start, "'%s' is not a number", number)
+	}
+	if value <= 0 {
+		return Sizing{}, p.fail(start, "the size has to be greater than zero")
+	}
+
+	switch {
+	case p.pos < len(p.source) && p.source[p.pos] == '%':
+		if value > 100 {
+			return Sizing{}, p.fail(start, "a percentage can not be greater than 100")
+		}
+		p.pos++
+		return Percent(value), nil
+	case strings.HasPrefix(string(p.source[p.pos:]), "fr"):
+		p.pos += 2
+		return Weight(value), nil
+	}
+	if value != float64(int(value)) {
+		return Sizing{}, p.fail(start, "a number of cells has to be whole, use '%%' or 'fr' for a share")
+	}
+	return Cells(int(value)), nil
+}
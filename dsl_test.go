@@ -0,0 +1,100 @@
+package bubbleboxer
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestParseLayout(t *testing.T) {
+	layout, err := ParseLayout("v(header:1, h(left:20%, main, right:30), footer : 1, h(a:2fr,b):2)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Layout{Vertical: true, Children: []Layout{
+		{Address: "header", Sizing: Cells(1)},
+		{Children: []Layout{
+			{Address: "left", Sizing: Percent(20)},
+			{Address: "main"},
+			{Address: "right", Sizing: Cells(30)},
+		}},
+		{Address: "footer", Sizing: Cells(1)},
+		{Sizing: Cells(2), Children: []Layout{
+			{Address: "a", Sizing: Weight(2)},
+			{Address: "b"},
+		}},
+	}}
+	if !reflect.DeepEqual(layout, want) {
+		t.Errorf("expected:\n%+v\nbut got:\n%+v", want, layout)
+	}
+
+	for source, column := range map[string]int{
+		"":              1,
+		"v(a,,b)":       5,
+		"v(a b)":        5,
+		"x(a)":          1,
+		"h(a, b:)":      8,
+		"h(a:1.5)":      5,
+		"h(a:0)":        5,
+		"h(a, b":        7,
+		"h(a, b) c":     9,
+		"v(a, h(b,c)))": 13,
+		"h(a, v(b, a))": 11,
+		"h(a:150%)":     5,
+	} {
+		_, err := ParseLayout(source)
+		var syntax *SyntaxError
+		if !errors.As(err, &syntax) {
+			t.Errorf("expected a SyntaxError for %q, but got: %v", source, err)
+			continue
+		}
+		if syntax.Column != column {
+			t.Errorf("expected the error for %q at column %d, but got: %v", source, column, err)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	b := Boxer{}
+	var err error
+	b.LayoutTree, err = b.Parse("v(top:1, h(left, right))", map[string]tea.Model{
+		"top":   testModel("t"),
+		"left":  testModel("l"),
+		"right": testModel("r"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.UpdateSize(tea.WindowSizeMsg{Width: 5, Height: 3}); err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Join([]string{
+		"t    ",
+		"──┬──",
+		"l │r ",
+	}, NEWLINE)
+	if got := b.View(); got != want {
+		t.Errorf("expected:\n%s\nbut got:\n%s", want, got)
+	}
+
+	// the addresses of the LayoutTree can not be used again
+	_, err = b.Parse("h(left, other)", map[string]tea.Model{"left": testModel("L"), "other": testModel("o")})
+	var e *Error
+	if !errors.As(err, &e) || e.Kind != KindInvalid || e.Address != "left" {
+		t.Errorf("expected a KindInvalid error for 'left', but got: %v", err)
+	}
+	if got := b.ModelMap["left"]; got != testModel("l") {
+		t.Errorf("expected the Model in the Layout-tree to be kept, but got %v", got)
+	}
+	if _, ok := b.ModelMap["other"]; ok {
+		t.Error("expected nothing to be added on a error")
+	}
+
+	_, err = b.Parse("h(other, missing)", map[string]tea.Model{"other": testModel("o")})
+	if !errors.As(err, &e) || e.Kind != KindNotFound || e.Address != "missing" {
+		t.Errorf("expected a KindNotFound error for 'missing', but got: %v", err)
+	}
+}
@@ -0,0 +1,105 @@
+package bubbleboxer
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrorKind tells what went wrong, so that it can be reacted differently on the kinds of errors.
+type ErrorKind int
+
+const (
+	// KindInvalid conveys that the layout-tree or a argument is not valid, for example a leaf with children.
+	KindInvalid ErrorKind = iota
+	// KindTooSmall conveys that for at leased one node or leaf in the Layout-tree there was not enough space left.
+	KindTooSmall
+	// KindOverflow conveys that a Model returned a View which is bigger than its leaf.
+	KindOverflow
+	// KindNotFound conveys that the address or path was not found.
+	KindNotFound
+	// KindConstraint conveys that the required Constraints can not be satisfied.
+	KindConstraint
+)
+
+func (k ErrorKind) String() string {
+	switch k {
+	case KindTooSmall:
+		return "too small"
+	case KindOverflow:
+		return "overflow"
+	case KindNotFound:
+		return "not found"
+	case KindConstraint:
+		return "constraint"
+	}
+	return "invalid"
+}
+
+// Size is the width and height of something. A zero value means that the dimension is not known or does not matter.
+type Size struct {
+	Width  int
+	Height int
+}
+
+func (s Size) String() string {
+	return fmt.Sprintf("%dx%d", s.Width, s.Height)
+}
+
+// Error is returned by the Boxer and describes where in the layout-tree what went wrong.
+// Use errors.As to get it from a returned error.
+type Error struct {
+	Kind ErrorKind
+
+	// Path holds the indices of the children from the root to the node where the error occurred.
+	Path []int
+	// Address is the address of the leaf where the error occurred, if it occurred in a leaf.
+	Address string
+
+	// Expected is the size which was available and Actual the size which was needed,
+	// if the error is about a size.
+	Expected Size
+	Actual   Size
+
+	// Err is the underlying error with a human readable message.
+	Err error
+}
+
+func (e *Error) Error() string {
+	var b strings.Builder
+	b.WriteString(e.Kind.String())
+	if len(e.Path) > 0 {
+		fmt.Fprintf(&b, " at path %v", e.Path)
+	}
+	if e.Address != "" {
+		fmt.Fprintf(&b, " in leaf '%s'", e.Address)
+	}
+	if e.Expected != (Size{}) || e.Actual != (Size{}) {
+		fmt.Fprintf(&b, " (%s available but %s needed)", e.Expected, e.Actual)
+	}
+	if e.Err != nil {
+		fmt.Fprintf(&b, ": %s", e.Err)
+	}
+	return b.String()
+}
+
+// Unwrap returns the underlying error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// newError is a shorthand for a Error of the kind with a formatted message.
+func newError(kind ErrorKind, format string, a ...interface{}) *Error {
+	return &Error{Kind: kind, Err: fmt.Errorf(format, a...)}
+}
+
+// childError adds the index of the child to the front of the Path of the error,
+// since the error occurred in this child. Errors which are no Error are wrapped as KindInvalid.
+func childError(index int, err error) error {
+	var e *Error
+	if !errors.As(err, &e) {
+		return &Error{Kind: KindInvalid, Path: []int{index}, Err: err}
+	}
+	e.Path = append([]int{index}, e.Path...)
+	return err
+}
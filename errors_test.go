@@ -0,0 +1,78 @@
+package bubbleboxer
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestErrors(t *testing.T) {
+	b := Boxer{}
+	b.LayoutTree = Node{
+		Children: []Node{
+			stripErr(b.CreateLeaf("a", testModel("a"))),
+			{
+				VerticalStacked: true,
+				Children: []Node{
+					stripErr(b.CreateLeaf("b", testModel("b"))),
+					stripErr(b.CreateLeaf("wide", testModel("too wide"))),
+				},
+			},
+		},
+	}
+
+	_, err := b.UpdateSize(tea.WindowSizeMsg{Width: 9, Height: 1})
+	var e *Error
+	if !errors.As(err, &e) || e.Kind != KindTooSmall {
+		t.Fatalf("expected a error of the kind too small but got: %v", err)
+	}
+	if !reflect.DeepEqual(e.Path, []int{1}) {
+		t.Errorf("expected the path to the vertical node but got %v", e.Path)
+	}
+	if !strings.Contains(err.Error(), "(4x1 available but 4x3 needed)") {
+		t.Errorf("expected the available and the needed size in the message but got: %v", err)
+	}
+
+	minimal := Boxer{}
+	minimal.LayoutTree = Node{
+		Children: []Node{
+			stripErr(minimal.CreateLeaf("a", testModel("a"))),
+			stripErr(minimal.CreateLeaf("b", testModel("b"))),
+			stripErr(minimal.CreateLeaf("c", testModel("c"))),
+		},
+	}
+	_, err = minimal.UpdateSize(tea.WindowSizeMsg{Width: 3, Height: 1})
+	e = nil
+	if !errors.As(err, &e) || e.Kind != KindTooSmall {
+		t.Fatalf("expected a error of the kind too small but got: %v", err)
+	}
+	if e.Expected != (Size{Width: 3, Height: 1}) || e.Actual != (Size{Width: 5, Height: 1}) {
+		t.Errorf("expected 3x1 to be available but 5x1 to be needed, got: %s and %s", e.Expected, e.Actual)
+	}
+
+	if _, err := b.UpdateSize(tea.WindowSizeMsg{Width: 9, Height: 3}); err != nil {
+		t.Fatal(err)
+	}
+	_, err = b.LayoutTree.render(&renderPass{modelMap: b.ModelMap, topology: topology{}})
+	e = nil
+	if !errors.As(err, &e) || e.Kind != KindOverflow {
+		t.Fatalf("expected a error of the kind overflow but got: %v", err)
+	}
+	if !reflect.DeepEqual(e.Path, []int{1, 1}) || e.Address != "wide" {
+		t.Errorf("expected the path and address of the wide leaf but got %v and '%s'", e.Path, e.Address)
+	}
+	if e.Expected.Width != 4 || e.Actual.Width != 8 {
+		t.Errorf("expected a width of 4 but a actual width of 8, got: %s and %s", e.Expected, e.Actual)
+	}
+	if errors.Unwrap(e) == nil {
+		t.Error("expected a underlying error")
+	}
+
+	_, err = b.Focus("missing")
+	if !errors.As(err, &e) || e.Kind != KindNotFound || e.Address != "missing" {
+		t.Errorf("expected a error of the kind not found but got: %v", err)
+	}
+}
@@ -0,0 +1,33 @@
+package bubbleboxer
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ErrorView returns the content which is shown instead of the View of a leaf, which could not be rendered.
+// The width and height are the space of the leaf, a result which does not fit is clipped.
+type ErrorView func(address string, err error, width, height int) string
+
+// ErrorStyle is used by DefaultErrorView.
+var ErrorStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
+
+// DefaultErrorView shows the address of the leaf and the error message wrapped to the width of the leaf.
+func DefaultErrorView(address string, err error, width, height int) string {
+	lines := []string{"error in '" + address + "':"}
+	lines = append(lines, strings.Split(err.Error(), "\n")...)
+	wrapped := OverflowWrap.apply(defaultMeasure, lines, width, height)
+	for i, line := range wrapped {
+		wrapped[i] = ErrorStyle.Render(line)
+	}
+	return strings.Join(wrapped, NEWLINE)
+}
+
+// renderError renders the error box of the leaf with the ErrorView of the pass.
+func (n *Node) renderError(pass *renderPass, err error) []string {
+	inner := n.ContentRect()
+	view := pass.errorView(n.address, err, inner.Width, inner.Height)
+	lines := isolate(OverflowClip.apply(pass.measure, strings.Split(view, NEWLINE), inner.Width, inner.Height))
+	return pass.measure.pad(lines, inner.Width, inner.Height)
+}
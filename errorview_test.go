@@ -0,0 +1,43 @@
+package bubbleboxer
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestLocalErrors(t *testing.T) {
+	b := Boxer{}
+	b.LayoutTree = Node{
+		Children: []Node{
+			stripErr(b.CreateLeaf("ok", testModel("fine"))),
+			stripErr(b.CreateLeaf("bad", testModel("far too wide"))),
+		},
+	}
+	if _, err := b.UpdateSize(tea.WindowSizeMsg{Width: 9, Height: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if got := b.View(); strings.Contains(got, "fine") {
+		t.Errorf("without LocalErrors the whole View should be replaced by the error, but got:\n%s", got)
+	}
+
+	b.LocalErrors = true
+	b.ErrorView = func(address string, err error, width, height int) string {
+		return fmt.Sprintf("%s!%dx%d", address, width, height)
+	}
+	want := strings.Join([]string{
+		"fine│bad!",
+		"    │    ",
+	}, NEWLINE)
+	if got := b.View(); got != want {
+		t.Errorf("expected:\n%s\nbut got:\n%s", want, got)
+	}
+
+	b.ErrorView = nil
+	got := b.View()
+	if !strings.HasPrefix(got, "fine│erro") {
+		t.Errorf("expected the default error box next to the healthy leaf but got:\n%s", got)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	boxer "github.com/treilik/bubbleboxer"
 )
 
@@ -26,7 +27,7 @@ func main() {
 	middle := viewPortHolder{v}
 	right := stringer(rightAddr)
 
-	lower := stringer(fmt.Sprintf("%s: use ctrl+c to quit", lowerAddr))
+	lower := stringer(fmt.Sprintf("%s: use alt+arrows to resize, alt+r to reset and ctrl+c to quit", lowerAddr))
 
 	// layout-tree defintion
 	m := model{tui: boxer.Boxer{}}
@@ -57,7 +58,12 @@ func main() {
 			stripErr(m.tui.CreateLeaf(lowerAddr, lower)),
 		},
 	}
-	p := tea.NewProgram(m)
+	// the focused leaf receives the key presses, here they scroll the viewport
+	m.tui.Focus(middleAddr)
+	// highlight the borders around the focused leaf
+	focusStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
+	m.tui.FocusStyle = &focusStyle
+	p := tea.NewProgram(m, tea.WithMouseCellMotion())
 	p.EnterAltScreen()
 	if err := p.Start(); err != nil {
 		fmt.Println(err)
@@ -74,7 +80,8 @@ type model struct {
 }
 
 func (m model) Init() tea.Cmd {
-	return spinner.Tick
+	// starts the spinner, since boxer calls Init of all Models
+	return m.tui.Init()
 }
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
@@ -82,37 +89,40 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		switch msg.String() {
 		case "q", "ctrl+c":
 			return m, tea.Quit
+		// resize the focused leaf with the keyboard, the separators can also be dragged with the mouse
+		case "alt+left":
+			cmd, _ := m.tui.ResizeFocused(-1, 0)
+			return m, cmd
+		case "alt+right":
+			cmd, _ := m.tui.ResizeFocused(1, 0)
+			return m, cmd
+		case "alt+up":
+			cmd, _ := m.tui.ResizeFocused(0, -1)
+			return m, cmd
+		case "alt+down":
+			cmd, _ := m.tui.ResizeFocused(0, 1)
+			return m, cmd
+		case "alt+r":
+			cmd, _ := m.tui.ResetSizes()
+			return m, cmd
 		}
 	case tea.WindowSizeMsg:
-		m.tui.UpdateSize(msg)
-	case spinner.TickMsg:
-		var cmd tea.Cmd
-		m.editModel(upperAddr, func(v tea.Model) (tea.Model, error) {
-			v, cmd = v.Update(msg)
-			return v, nil
-		})
+		cmd, _ := m.tui.UpdateSize(msg)
 		return m, cmd
 	}
-	return m, nil
+	// the key presses are delivered to the focused leaf
+	// and the messages of the commands (like the spinner ticks) are delivered to the leaf which issued them
+	var cmd tea.Cmd
+	m.tui, cmd = m.updateTui(msg)
+	return m, cmd
 }
 func (m model) View() string {
 	return m.tui.View()
 }
 
-func (m *model) editModel(addr string, edit func(tea.Model) (tea.Model, error)) error {
-	if edit == nil {
-		return fmt.Errorf("no edit function provided")
-	}
-	v, ok := m.tui.ModelMap[addr]
-	if !ok {
-		return fmt.Errorf("no model with address '%s' found", addr)
-	}
-	v, err := edit(v)
-	if err != nil {
-		return err
-	}
-	m.tui.ModelMap[addr] = v
-	return nil
+func (m model) updateTui(msg tea.Msg) (boxer.Boxer, tea.Cmd) {
+	tui, cmd := m.tui.Update(msg)
+	return tui.(boxer.Boxer), cmd
 }
 
 type stringer string
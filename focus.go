@@ -0,0 +1,132 @@
+package bubbleboxer
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// FocusMsg is send to a Model when its leaf gains the focus.
+type FocusMsg struct{}
+
+// BlurMsg is send to a Model when its leaf loses the focus.
+type BlurMsg struct{}
+
+// Focused returns the address of the focused leaf or an empty string if no leaf is focused.
+func (b *Boxer) Focused() string {
+	return b.focus
+}
+
+// Focus sets the focus to the leaf with the given address.
+// The Model which loses the focus receives a BlurMsg and the Model which gains the focus receives a FocusMsg.
+// The returned tea.Cmd holds the commands returned by both Models.
+func (b *Boxer) Focus(address string) (tea.Cmd, error) {
+	if !b.LayoutTree.contains(address) {
+		return nil, &Error{Kind: KindNotFound, Address: address, Err: fmt.Errorf("no leaf with address '%s' found in the layout-tree", address)}
+	}
+	if address == b.focus {
+		return nil, nil
+	}
+
+	var cmds []tea.Cmd
+	if _, ok := b.ModelMap[b.focus]; ok {
+		cmds = append(cmds, b.updateModel(b.focus, BlurMsg{}))
+	}
+	b.focus = address
+	cmds = append(cmds, b.updateModel(address, FocusMsg{}))
+	return tea.Batch(cmds...), nil
+}
+
+// FocusNext moves the focus to the next leaf of the LayoutTree.
+// The leaves are ordered as they appear in the LayoutTree and after the last leaf the first one is focused again.
+func (b *Boxer) FocusNext() tea.Cmd {
+	return b.focusStep(1)
+}
+
+// FocusPrev moves the focus to the previous leaf of the LayoutTree.
+// The leaves are ordered as they appear in the LayoutTree and before the first leaf the last one is focused again.
+func (b *Boxer) FocusPrev() tea.Cmd {
+	return b.focusStep(-1)
+}
+
+func (b *Boxer) focusStep(step int) tea.Cmd {
+	addresses := b.LayoutTree.leaves()
+	length := len(addresses)
+	if length == 0 {
+		return nil
+	}
+
+	// start before the first (or after the last) leaf if nothing is focused yet
+	current := -1
+	if step < 0 {
+		current = length
+	}
+	for i, address := range addresses {
+		if address == b.focus {
+			current = i
+			break
+		}
+	}
+	next := ((current+step)%length + length) % length
+
+	// the address is taken from the LayoutTree and thus can not be missing
+	cmd, _ := b.Focus(addresses[next])
+	return cmd
+}
+
+// updateModel sends the msg to the Model with the given address and saves back the changed Model.
+// The returned command is wrapped (see AddressedMsg).
+func (b *Boxer) updateModel(address string, msg tea.Msg) tea.Cmd {
+	v, ok := b.ModelMap[address]
+	if !ok {
+		return nil
+	}
+	v, cmd := v.Update(msg)
+	b.ModelMap[address] = v
+	return wrapCmd(address, cmd)
+}
+
+// leaves returns the addresses of all leaves in the order they appear in the layout-tree.
+// An address which is used by multiple leaves is only returned once.
+func (n *Node) leaves() []string {
+	var addresses []string
+	seen := make(map[string]bool)
+	n.walk(func(node *Node) {
+		if !node.IsLeaf() || seen[node.address] {
+			return
+		}
+		seen[node.address] = true
+		addresses = append(addresses, node.address)
+	})
+	return addresses
+}
+
+// contains returns if a leaf with the given address is part of the tree.
+func (n *Node) contains(address string) bool {
+	return n.pathTo(address) != nil
+}
+
+// pathTo returns the indices of the children from this node to the first leaf with the given address
+// or nil if there is no such leaf.
+func (n *Node) pathTo(address string) []int {
+	if address == "" {
+		return nil
+	}
+	if n.address == address {
+		return []int{}
+	}
+	for i := range n.Children {
+		if sub := n.Children[i].pathTo(address); sub != nil {
+			return append([]int{i}, sub...)
+		}
+	}
+	return nil
+}
+
+// walk calls visit for the node and all its descendants in depth-first order.
+func (n *Node) walk(visit func(*Node)) {
+	visit(n)
+	for i := range n.Children {
+		n.Children[i].walk(visit)
+	}
+}
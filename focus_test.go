@@ -0,0 +1,73 @@
+package bubbleboxer
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// focusModel remembers if it is focused and how many keys it has received
+type focusModel struct {
+	focused bool
+	keys    int
+}
+
+func (f focusModel) Init() tea.Cmd { return nil }
+func (f focusModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg.(type) {
+	case FocusMsg:
+		f.focused = true
+	case BlurMsg:
+		f.focused = false
+	case tea.KeyMsg:
+		f.keys++
+	}
+	return f, nil
+}
+func (f focusModel) View() string { return "" }
+
+func TestFocus(t *testing.T) {
+	b := Boxer{}
+	b.LayoutTree = Node{
+		Children: []Node{
+			stripErr(b.CreateLeaf("a", focusModel{})),
+			stripErr(b.CreateLeaf("b", focusModel{})),
+		},
+	}
+
+	if _, err := b.Focus("missing"); err == nil {
+		t.Error("focusing a address which is not in the layout-tree should return an error")
+	}
+	if _, err := b.Focus("a"); err != nil {
+		t.Error(err)
+	}
+	if !b.ModelMap["a"].(focusModel).focused {
+		t.Error("the focused model should have received a FocusMsg")
+	}
+
+	b.FocusNext()
+	if b.Focused() != "b" {
+		t.Errorf("after FocusNext the focus should be on 'b' but is on '%s'", b.Focused())
+	}
+	if b.ModelMap["a"].(focusModel).focused {
+		t.Error("the model which lost the focus should have received a BlurMsg")
+	}
+
+	b.FocusNext()
+	if b.Focused() != "a" {
+		t.Errorf("FocusNext should wrap around to the first leaf but focus is on '%s'", b.Focused())
+	}
+	b.FocusPrev()
+	if b.Focused() != "b" {
+		t.Errorf("FocusPrev should wrap around to the last leaf but focus is on '%s'", b.Focused())
+	}
+
+	model, _ := b.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	b = model.(Boxer)
+	if keys := b.ModelMap["b"].(focusModel).keys; keys != 1 {
+		t.Errorf("the focused model should have received one key but got %d", keys)
+	}
+	if keys := b.ModelMap["a"].(focusModel).keys; keys != 0 {
+		t.Errorf("a not focused model should not receive keys but got %d", keys)
+	}
+}
@@ -0,0 +1,93 @@
+package bubbleboxer
+
+import (
+	"strings"
+)
+
+// Align is the position of a label within the edge of a Frame.
+type Align int
+
+const (
+	// AlignLeft places the label right after the left corner.
+	AlignLeft Align = iota
+	// AlignCenter places the label in the middle of the edge.
+	AlignCenter
+	// AlignRight places the label right before the right corner.
+	AlignRight
+)
+
+// Frame is a box drawn around a node or a leaf with the characters of the Border of the node.
+// The frame uses one cell on every side, which is not available for the content.
+type Frame struct {
+	// Title is embedded in the top edge of the frame and cut off if it is too long.
+	Title      string `json:"title,omitempty" yaml:"title,omitempty"`
+	TitleAlign Align  `json:"titleAlign,omitempty" yaml:"titleAlign,omitempty"`
+
+	// Footer is embedded in the bottom edge of the frame and cut off if it is too long.
+	Footer      string `json:"footer,omitempty" yaml:"footer,omitempty"`
+	FooterAlign Align  `json:"footerAlign,omitempty" yaml:"footerAlign,omitempty"`
+}
+
+// frameWidth returns how many cells the frame uses on each side.
+func (n *Node) frameWidth() int {
+	if n.Frame == nil {
+		return 0
+	}
+	return 1
+}
+
+// frameSeparators adds the cells of the frame to the topology.
+func (n *Node) frameSeparators(t topology, l look) {
+	if n.Frame == nil || n.width < 2 || n.height < 2 {
+		return
+	}
+	right, bottom := n.x+n.width-1, n.y+n.height-1
+	for x := n.x + 1; x < right; x++ {
+		t.mark(point{x, n.y}, armLeft|armRight, l)
+		t.mark(point{x, bottom}, armLeft|armRight, l)
+	}
+	for y := n.y + 1; y < bottom; y++ {
+		t.mark(point{n.x, y}, armUp|armDown, l)
+		t.mark(point{right, y}, armUp|armDown, l)
+	}
+	t.mark(point{n.x, n.y}, armDown|armRight, l)
+	t.mark(point{right, n.y}, armDown|armLeft, l)
+	t.mark(point{n.x, bottom}, armUp|armRight, l)
+	t.mark(point{right, bottom}, armUp|armLeft, l)
+}
+
+// renderFrame draws the frame around the lines of the content, which have to fill the inner area.
+func (n *Node) renderFrame(pass *renderPass, content []string) []string {
+	lines := make([]string, 0, n.height)
+	lines = append(lines, n.frameEdge(pass, n.y, n.Frame.Title, n.Frame.TitleAlign))
+	right := n.x + n.width - 1
+	for i, line := range content {
+		y := n.y + 1 + i
+		lines = append(lines, pass.separator(n.x, y)+line+pass.separator(right, y))
+	}
+	return append(lines, n.frameEdge(pass, n.y+n.height-1, n.Frame.Footer, n.Frame.FooterAlign))
+}
+
+// frameEdge returns the top or bottom edge of the frame with the label embedded between the corners.
+func (n *Node) frameEdge(pass *renderPass, y int, label string, align Align) string {
+	space := n.width - 2
+	label = pass.measure.clip(label, space, "")
+	labelWidth := pass.measure.lineWidth(label)
+	if labelWidth == 0 {
+		return pass.horizontalLine(n.x, y, n.width)
+	}
+
+	var offset int
+	switch align {
+	case AlignCenter:
+		offset = (space - labelWidth) / 2
+	case AlignRight:
+		offset = space - labelWidth
+	}
+	var edge strings.Builder
+	edge.WriteString(pass.horizontalLine(n.x, y, 1+offset))
+	edge.WriteString(label)
+	start := n.x + 1 + offset + labelWidth
+	edge.WriteString(pass.horizontalLine(start, y, n.x+n.width-start))
+	return edge.String()
+}
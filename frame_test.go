@@ -0,0 +1,86 @@
+package bubbleboxer
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// frameModel shows the size it got
+type frameModel struct {
+	size tea.WindowSizeMsg
+}
+
+func (m frameModel) Init() tea.Cmd { return nil }
+func (m frameModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if size, ok := msg.(tea.WindowSizeMsg); ok {
+		m.size = size
+	}
+	return m, nil
+}
+func (m frameModel) View() string { return fmt.Sprintf("%dx%d", m.size.Width, m.size.Height) }
+
+func TestFrame(t *testing.T) {
+	b := Boxer{}
+	b.LayoutTree = Node{
+		Frame: &Frame{Title: "top", TitleAlign: AlignCenter},
+		Children: []Node{
+			stripErr(b.CreateLeaf("a", testModel("a"))),
+			stripErr(b.CreateLeaf("b", testModel("b"))),
+		},
+	}
+	if _, err := b.UpdateSize(tea.WindowSizeMsg{Width: 9, Height: 4}); err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Join([]string{
+		"┌──top──┐",
+		"│a  │b  │",
+		"│   │   │",
+		"└───┴───┘",
+	}, NEWLINE)
+	if got := b.View(); got != want {
+		t.Errorf("expected:\n%s\nbut got:\n%s", want, got)
+	}
+
+	leaf := stripErr(b.CreateLeaf("c", frameModel{}))
+	leaf.Frame = &Frame{Title: "a long title", Footer: "f", FooterAlign: AlignRight}
+	header := stripErr(b.CreateLeaf("a", testModel("a")))
+	header.Sizing = Cells(1)
+	b.LayoutTree = Node{
+		VerticalStacked: true,
+		Children:        []Node{header, leaf},
+	}
+	if _, err := b.UpdateSize(tea.WindowSizeMsg{Width: 6, Height: 5}); err != nil {
+		t.Fatal(err)
+	}
+	want = strings.Join([]string{
+		"a     ",
+		"──────",
+		"┌a lo┐",
+		"│4x1 │",
+		"└───f┘",
+	}, NEWLINE)
+	if got := b.View(); got != want {
+		t.Errorf("expected:\n%s\nbut got:\n%s", want, got)
+	}
+
+	if address, ok := b.LeafAt(0, 3); !ok || address != "c" {
+		t.Errorf("expected the framed leaf at 0,3 but got '%s'", address)
+	}
+
+	if _, err := b.UpdateSize(tea.WindowSizeMsg{Width: 6, Height: 4}); err == nil {
+		t.Error("expected an error since there is no space within the frame")
+	}
+
+	// the frame is also respected by the constraints
+	b.LayoutTree.Frame = &Frame{}
+	b.Constraints = []Constraint{LeafEdge("a", EdgeHeight).Equal(Value(2))}
+	if _, err := b.UpdateSize(tea.WindowSizeMsg{Width: 6, Height: 9}); err != nil {
+		t.Fatal(err)
+	}
+	if c := b.LayoutTree.at(b.LayoutTree.pathTo("c")); c.GetHeight() != 4 || c.GetWidth() != 4 {
+		t.Errorf("expected the framed leaf to be 4x4 within the frame of the root but got %dx%d", c.GetWidth(), c.GetHeight())
+	}
+}
@@ -0,0 +1,47 @@
+package bubbleboxer
+
+import "fmt"
+
+// Rect is a rectangle on the screen, X and Y are the absolute position of the top left cell.
+type Rect struct {
+	X, Y          int
+	Width, Height int
+}
+
+func (r Rect) String() string {
+	return fmt.Sprintf("%dx%d+%d+%d", r.Width, r.Height, r.X, r.Y)
+}
+
+// Contains returns if the absolute position is within the rectangle.
+func (r Rect) Contains(x, y int) bool {
+	return x >= r.X && x < r.X+r.Width && y >= r.Y && y < r.Y+r.Height
+}
+
+// borders returns if the absolute position is directly next to the rectangle, including the corners.
+func (r Rect) borders(x, y int) bool {
+	if x < r.X-1 || x > r.X+r.Width || y < r.Y-1 || y > r.Y+r.Height {
+		return false
+	}
+	return x == r.X-1 || x == r.X+r.Width || y == r.Y-1 || y == r.Y+r.Height
+}
+
+// Rect returns where the node (including its frame) was placed on the screen by the last UpdateSize.
+func (n *Node) Rect() Rect {
+	return Rect{X: n.x, Y: n.y, Width: n.width, Height: n.height}
+}
+
+// ContentRect returns the area of the node without its frame, in which the children or the Model are drawn.
+func (n *Node) ContentRect() Rect {
+	f := n.frameWidth()
+	return Rect{X: n.x + f, Y: n.y + f, Width: n.width - 2*f, Height: n.height - 2*f}
+}
+
+// RectOf returns where the leaf with the address is drawn on the screen, including its frame.
+// Use the ContentRect of the leaf for the area in which the Model is drawn.
+func (b *Boxer) RectOf(address string) (Rect, error) {
+	leaf := b.LayoutTree.at(b.LayoutTree.pathTo(address))
+	if leaf == nil || !leaf.IsLeaf() {
+		return Rect{}, &Error{Kind: KindNotFound, Address: address, Err: fmt.Errorf("no leaf with address '%s' found in the layout-tree", address)}
+	}
+	return leaf.Rect(), nil
+}
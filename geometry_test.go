@@ -0,0 +1,54 @@
+package bubbleboxer
+
+import (
+	"errors"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestRect(t *testing.T) {
+	b := Boxer{}
+	framed := stripErr(b.CreateLeaf("framed", testModel("")))
+	framed.Frame = &Frame{}
+	b.LayoutTree = Node{
+		VerticalStacked: true,
+		Children: []Node{
+			stripErr(b.CreateLeaf("top", testModel(""))),
+			{Children: []Node{
+				stripErr(b.CreateLeaf("left", testModel(""))),
+				framed,
+			}},
+		},
+	}
+	if _, err := b.UpdateSize(tea.WindowSizeMsg{Width: 11, Height: 9}); err != nil {
+		t.Fatal(err)
+	}
+
+	for address, want := range map[string]Rect{
+		"top":    {X: 0, Y: 0, Width: 11, Height: 4},
+		"left":   {X: 0, Y: 5, Width: 5, Height: 4},
+		"framed": {X: 6, Y: 5, Width: 5, Height: 4},
+	} {
+		got, err := b.RectOf(address)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("expected '%s' at %s but got %s", address, want, got)
+		}
+	}
+
+	leaf := b.LayoutTree.at([]int{1, 1})
+	if got, want := leaf.ContentRect(), (Rect{X: 7, Y: 6, Width: 3, Height: 2}); got != want {
+		t.Errorf("expected the content of the framed leaf at %s but got %s", want, got)
+	}
+	if got, want := b.LayoutTree.Children[1].Rect(), (Rect{X: 0, Y: 5, Width: 11, Height: 4}); got != want {
+		t.Errorf("expected the node at %s but got %s", want, got)
+	}
+
+	var e *Error
+	if _, err := b.RectOf("missing"); !errors.As(err, &e) || e.Kind != KindNotFound {
+		t.Errorf("expected a not found error but got: %v", err)
+	}
+}
@@ -5,19 +5,20 @@ go 1.17
 require (
 	github.com/charmbracelet/bubbles v0.14.0
 	github.com/charmbracelet/bubbletea v0.21.0
-	github.com/muesli/ansi v0.0.0-20211018074035-2e021307bc4b
+	github.com/charmbracelet/lipgloss v0.5.0
+	github.com/mattn/go-runewidth v0.0.13
+	github.com/muesli/termenv v0.11.1-0.20220212125758-44cd13922739
+	github.com/rivo/uniseg v0.2.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
-	github.com/charmbracelet/lipgloss v0.5.0 // indirect
 	github.com/containerd/console v1.0.3 // indirect
 	github.com/lucasb-eyer/go-colorful v1.2.0 // indirect
 	github.com/mattn/go-isatty v0.0.14 // indirect
-	github.com/mattn/go-runewidth v0.0.13 // indirect
+	github.com/muesli/ansi v0.0.0-20211018074035-2e021307bc4b // indirect
 	github.com/muesli/cancelreader v0.2.0 // indirect
 	github.com/muesli/reflow v0.3.0 // indirect
-	github.com/muesli/termenv v0.11.1-0.20220212125758-44cd13922739 // indirect
-	github.com/rivo/uniseg v0.2.0 // indirect
 	golang.org/x/sys v0.0.0-20220209214540-3681064d5158 // indirect
 	golang.org/x/term v0.0.0-20210927222741-03fcf44c2211 // indirect
 )
@@ -0,0 +1,303 @@
+package bubbleboxer
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Grid arranges the children of a node in rows and columns.
+// Unlike the VerticalStacked orientation a child can span multiple rows and columns.
+type Grid struct {
+	// Rows and Columns define the tracks of the grid,
+	// the space is distributed between them like between the children of a node (see Sizing).
+	Rows    []Sizing
+	Columns []Sizing
+
+	// Cells places the children in the grid, Cells[i] belongs to Children[i] of the node.
+	// The Cells of two children should not overlap and a cell without a child is left empty.
+	Cells []Cell
+}
+
+// Cell places a child within a Grid. The spans are treated as one if they are zero or less.
+type Cell struct {
+	Row    int `json:"row" yaml:"row"`
+	Column int `json:"column" yaml:"column"`
+
+	RowSpan    int `json:"rowSpan,omitempty" yaml:"rowSpan,omitempty"`
+	ColumnSpan int `json:"columnSpan,omitempty" yaml:"columnSpan,omitempty"`
+}
+
+func (c Cell) rowSpan() int {
+	if c.RowSpan <= 0 {
+		return 1
+	}
+	return c.RowSpan
+}
+
+func (c Cell) columnSpan() int {
+	if c.ColumnSpan <= 0 {
+		return 1
+	}
+	return c.ColumnSpan
+}
+
+// owners returns the index of the child for every cell of the grid or -1 if the cell is empty.
+func (g *Grid) owners(children int) ([][]int, error) {
+	if len(g.Rows) == 0 || len(g.Columns) == 0 {
+		return nil, newError(KindInvalid, "a grid needs at least one row and one column")
+	}
+	if len(g.Cells) != children {
+		return nil, newError(KindInvalid, "the grid has %d cells but want one for each child and thus: %d", len(g.Cells), children)
+	}
+	owners := make([][]int, len(g.Rows))
+	for r := range owners {
+		owners[r] = make([]int, len(g.Columns))
+		for c := range owners[r] {
+			owners[r][c] = -1
+		}
+	}
+	for i, cell := range g.Cells {
+		if cell.Row < 0 || cell.Column < 0 || cell.Row+cell.rowSpan() > len(g.Rows) || cell.Column+cell.columnSpan() > len(g.Columns) {
+			return nil, newError(KindInvalid, "the cell of the %d child is outside of the %dx%d grid", i, len(g.Rows), len(g.Columns))
+		}
+		for r := cell.Row; r < cell.Row+cell.rowSpan(); r++ {
+			for c := cell.Column; c < cell.Column+cell.columnSpan(); c++ {
+				if owners[r][c] != -1 {
+					return nil, newError(KindInvalid, "the cells of the %d and the %d child overlap", owners[r][c], i)
+				}
+				owners[r][c] = i
+			}
+		}
+	}
+	return owners, nil
+}
+
+// CreateGridNode is a constructor for a Node which arranges its children in a grid with the given tracks.
+// Add the children with Place.
+func CreateGridNode(rows, columns []Sizing) Node {
+	return Node{Grid: &Grid{Rows: rows, Columns: columns}}
+}
+
+// Place adds the child to the grid node within the given cell.
+func (n *Node) Place(child Node, cell Cell) error {
+	if n.Grid == nil {
+		return newError(KindInvalid, "only a grid node (see CreateGridNode) can place children in cells")
+	}
+	n.Children = append(n.Children, child)
+	n.Grid.Cells = append(n.Grid.Cells, cell)
+	return nil
+}
+
+// updateGridSize distributes the space between the tracks of the grid and resizes the children according to there cells.
+func (n *Node) updateGridSize(size tea.WindowSizeMsg, pass *sizePass) error {
+	if _, err := n.Grid.owners(len(n.Children)); err != nil {
+		return err
+	}
+
+	border := n.borderWidth()
+	rows, err := resolveSizes(n.Grid.Rows, size.Height-border*(len(n.Grid.Rows)-1))
+	if err != nil {
+		return n.tooSmall(err)
+	}
+	columns, err := resolveSizes(n.Grid.Columns, size.Width-border*(len(n.Grid.Columns)-1))
+	if err != nil {
+		return n.tooSmall(err)
+	}
+	n.gridRows, n.gridColumns = rows, columns
+
+	inner := n.ContentRect()
+	for i := range n.Children {
+		c := &n.Children[i]
+		cell := n.Grid.Cells[i]
+		var y, x, height, width int
+		y, height = trackSpan(rows, cell.Row, cell.rowSpan(), border)
+		x, width = trackSpan(columns, cell.Column, cell.columnSpan(), border)
+		c.x, c.y = inner.X+x, inner.Y+y
+
+		err := c.updateSize(tea.WindowSizeMsg{Width: width, Height: height}, pass)
+		if err != nil {
+			return childError(i, err)
+		}
+	}
+	return nil
+}
+
+// trackSpan returns the offset and the size of the span of tracks including the separators within it.
+func trackSpan(tracks []int, start, span, border int) (int, int) {
+	var offset, size int
+	for i := 0; i < start; i++ {
+		offset += tracks[i] + border
+	}
+	for i := start; i < start+span; i++ {
+		size += tracks[i]
+	}
+	size += border * (span - 1)
+	return offset, size
+}
+
+// band is a row or a column of cells in a grid, which is either within a track or a separator between two tracks
+type band struct {
+	start int
+	size  int
+
+	// track is the index of the track or, for a separator, the index of the track after it
+	track     int
+	separator bool
+}
+
+// bands returns the bands of the tracks and separators, relative to the start of the grid.
+func bands(tracks []int, border int) []band {
+	var result []band
+	var offset int
+	for i, size := range tracks {
+		if i > 0 && border > 0 {
+			result = append(result, band{start: offset, size: border, track: i, separator: true})
+			offset += border
+		}
+		result = append(result, band{start: offset, size: size, track: i})
+		offset += size
+	}
+	return result
+}
+
+// gridSeparators adds the separator cells between the tracks of the grid to the topology.
+// Between the cells of a child spanning multiple tracks no separator is drawn.
+func (n *Node) gridSeparators(t topology, l look) {
+	owners, err := n.Grid.owners(len(n.Children))
+	if err != nil || len(n.gridRows) != len(owners) || len(n.gridColumns) != len(owners[0]) {
+		return
+	}
+
+	// verticalSeparator and horizontalSeparator return if there is a separator between the given track
+	// and the one before it, within the given track of the other dimension
+	verticalSeparator := func(row, column int) bool {
+		if row < 0 || row >= len(owners) {
+			return false
+		}
+		return owners[row][column-1] == -1 || owners[row][column-1] != owners[row][column]
+	}
+	horizontalSeparator := func(row, column int) bool {
+		if column < 0 || column >= len(owners[row]) {
+			return false
+		}
+		return owners[row-1][column] == -1 || owners[row-1][column] != owners[row][column]
+	}
+
+	inner := n.ContentRect()
+	width := n.borderWidth()
+	for _, rowBand := range bands(n.gridRows, width) {
+		for _, columnBand := range bands(n.gridColumns, width) {
+			if !rowBand.separator && !columnBand.separator {
+				continue
+			}
+			row, column := rowBand.track, columnBand.track
+
+			var a arms
+			switch {
+			case !rowBand.separator:
+				if verticalSeparator(row, column) {
+					a = armUp | armDown
+				}
+			case !columnBand.separator:
+				if horizontalSeparator(row, column) {
+					a = armLeft | armRight
+				}
+			default:
+				if verticalSeparator(row-1, column) {
+					a |= armUp
+				}
+				if verticalSeparator(row, column) {
+					a |= armDown
+				}
+				if horizontalSeparator(row, column-1) {
+					a |= armLeft
+				}
+				if horizontalSeparator(row, column) {
+					a |= armRight
+				}
+			}
+			if a == 0 {
+				continue
+			}
+			for y := inner.Y + rowBand.start; y < inner.Y+rowBand.start+rowBand.size; y++ {
+				for x := inner.X + columnBand.start; x < inner.X+columnBand.start+columnBand.size; x++ {
+					t.mark(point{x, y}, a, l)
+				}
+			}
+		}
+	}
+}
+
+func (n *Node) renderGrid(pass *renderPass) ([]string, error) {
+	if _, err := n.Grid.owners(len(n.Children)); err != nil {
+		return nil, err
+	}
+
+	// render all children first
+	children := make([][]string, len(n.Children))
+	for i := range n.Children {
+		lines, err := n.Children[i].render(pass)
+		if err != nil {
+			return lines, childError(i, err)
+		}
+		children[i] = lines
+	}
+
+	inner := n.ContentRect()
+	border := n.borderWidth()
+	rowBands := bands(n.gridRows, border)
+	columnBands := bands(n.gridColumns, border)
+
+	// the space which is left empty by the tracks
+	var rest string
+	if len(columnBands) > 0 && inner.Width > columnBands[len(columnBands)-1].start+columnBands[len(columnBands)-1].size {
+		last := columnBands[len(columnBands)-1]
+		rest = strings.Repeat(SPACE, inner.Width-last.start-last.size)
+	}
+
+	lines := make([]string, 0, inner.Height)
+	for _, rowBand := range rowBands {
+		for l := 0; l < rowBand.size; l++ {
+			y := inner.Y + rowBand.start + l
+			var line strings.Builder
+			for b := 0; b < len(columnBands); b++ {
+				columnBand := columnBands[b]
+				x := inner.X + columnBand.start
+
+				// the content of a child covers all bands within its rectangle
+				if i := n.childAt(x, y); i >= 0 {
+					child := n.Children[i]
+					line.WriteString(children[i][y-child.y])
+					for b+1 < len(columnBands) && inner.X+columnBands[b+1].start < child.x+child.width {
+						b++
+					}
+					continue
+				}
+
+				if !rowBand.separator && !columnBand.separator {
+					// empty cell
+					line.WriteString(strings.Repeat(SPACE, columnBand.size))
+					continue
+				}
+				line.WriteString(pass.horizontalLine(x, y, columnBand.size))
+			}
+			line.WriteString(rest)
+			lines = append(lines, line.String())
+		}
+	}
+	for len(lines) < inner.Height {
+		lines = append(lines, strings.Repeat(SPACE, inner.Width))
+	}
+	return lines, nil
+}
+
+// childAt returns the index of the child which covers the absolute position or -1 if there is none.
+func (n *Node) childAt(x, y int) int {
+	for i := range n.Children {
+		if n.Children[i].covers(x, y) {
+			return i
+		}
+	}
+	return -1
+}
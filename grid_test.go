@@ -0,0 +1,87 @@
+package bubbleboxer
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestGrid(t *testing.T) {
+	b := Boxer{}
+	grid := CreateGridNode(
+		[]Sizing{Cells(1), {}, Cells(1)},
+		[]Sizing{Cells(3), {}, Cells(3)},
+	)
+	for _, place := range []struct {
+		address string
+		cell    Cell
+	}{
+		{"header", Cell{Row: 0, Column: 0, ColumnSpan: 3}},
+		{"l", Cell{Row: 1, Column: 0}},
+		{"m", Cell{Row: 1, Column: 1}},
+		{"r", Cell{Row: 1, Column: 2}},
+		{"f", Cell{Row: 2, Column: 0, ColumnSpan: 2}},
+	} {
+		if err := grid.Place(stripErr(b.CreateLeaf(place.address, testModel(place.address))), place.cell); err != nil {
+			t.Fatal(err)
+		}
+	}
+	b.LayoutTree = grid
+
+	if _, err := b.UpdateSize(tea.WindowSizeMsg{Width: 11, Height: 7}); err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Join([]string{
+		"header     ",
+		"───┬───┬───",
+		"l  │m  │r  ",
+		"   │   │   ",
+		"   │   │   ",
+		"───┴───┼───",
+		"f      │   ",
+	}, NEWLINE)
+	if got := b.View(); got != want {
+		t.Errorf("expected:\n%s\nbut got:\n%s", want, got)
+	}
+
+	if address, ok := b.LeafAt(5, 6); !ok || address != "f" {
+		t.Errorf("expected the spanning footer at 5,6 but got '%s'", address)
+	}
+
+	// the separators of the grid are reported with the tracks before them
+	for _, c := range []struct{ x, y, row, column int }{
+		{7, 3, -1, 1},
+		{5, 5, 1, -1},
+		{7, 5, 1, 1},
+	} {
+		_, cmd := b.Update(tea.MouseMsg{X: c.x, Y: c.y, Type: tea.MouseLeft})
+		msgs := collectMsgs(cmd)
+		if len(msgs) != 1 {
+			t.Errorf("expected a SeparatorMsg at %d,%d but got %v", c.x, c.y, msgs)
+			continue
+		}
+		if sep, ok := msgs[0].(SeparatorMsg); !ok || !sep.Grid || sep.Row != c.row || sep.Column != c.column {
+			t.Errorf("expected the separator after row %d and column %d at %d,%d but got %#v", c.row, c.column, c.x, c.y, msgs[0])
+		}
+	}
+
+	// the separators of the grid can not be dragged
+	b.Update(tea.MouseMsg{X: 3, Y: 3, Type: tea.MouseLeft})
+	b.Update(tea.MouseMsg{X: 5, Y: 3, Type: tea.MouseMotion})
+	b.Update(tea.MouseMsg{X: 5, Y: 3, Type: tea.MouseRelease})
+	if got := b.View(); got != want {
+		t.Errorf("expected the grid to keep its sizes after a drag but got:\n%s", got)
+	}
+	var e *Error
+	if _, err := b.MoveSeparator([]int{}, 0, 2); !errors.As(err, &e) || e.Kind != KindInvalid {
+		t.Errorf("expected a error of the KindInvalid for moving a separator of a grid, but got: %v", err)
+	}
+
+	grid.Grid.Cells[4].Row = 1
+	b.LayoutTree = grid
+	if _, err := b.UpdateSize(tea.WindowSizeMsg{Width: 11, Height: 7}); err == nil {
+		t.Error("expected an error since the footer overlaps with the main leaf")
+	}
+}
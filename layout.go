@@ -0,0 +1,204 @@
+package bubbleboxer
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Layout is the declarative form of a layout-tree, which can be marshalled to and from JSON and YAML.
+// Leaves have a Address, nodes have Children. The SizeFunc's and the lipgloss styles of the nodes can not be described.
+type Layout struct {
+	// Address makes this a leaf, its Model is made by the factory of the Registry for the Type or this Address.
+	Address string `json:"address,omitempty" yaml:"address,omitempty"`
+	// Type is the name of the factory in the Registry, if it is empty the Address is used.
+	Type string `json:"type,omitempty" yaml:"type,omitempty"`
+
+	// Vertical stacks the Children on top of each other instead of side by side.
+	Vertical bool `json:"vertical,omitempty" yaml:"vertical,omitempty"`
+	// NoBorder omits the separators between the Children (see CreateNoBorderNode).
+	NoBorder bool `json:"noBorder,omitempty" yaml:"noBorder,omitempty"`
+
+	// Rows and Columns make this a grid node, the Children are placed by there Cell.
+	Rows    []Sizing `json:"rows,omitempty" yaml:"rows,omitempty"`
+	Columns []Sizing `json:"columns,omitempty" yaml:"columns,omitempty"`
+	// Cell places this node within the parent, if the parent is a grid.
+	Cell *Cell `json:"cell,omitempty" yaml:"cell,omitempty"`
+
+	Sizing   Sizing   `json:"sizing,omitempty" yaml:"sizing,omitempty"`
+	Border   *Border  `json:"border,omitempty" yaml:"border,omitempty"`
+	Frame    *Frame   `json:"frame,omitempty" yaml:"frame,omitempty"`
+	Overflow Overflow `json:"overflow,omitempty" yaml:"overflow,omitempty"`
+
+	// Sizes are the sizes of the Children set by dragging the separators (see Node.Sizes).
+	Sizes []int `json:"sizes,omitempty" yaml:"sizes,omitempty"`
+
+	Children []Layout `json:"children,omitempty" yaml:"children,omitempty"`
+}
+
+// ModelFactory makes the Model for the leaf with the address.
+type ModelFactory func(address string) (tea.Model, error)
+
+// Registry maps the types or addresses of the leaves of a Layout to the factories of there Models.
+type Registry map[string]ModelFactory
+
+// model makes the Model for the leaf of the Layout.
+func (r Registry) model(l Layout) (tea.Model, error) {
+	name := l.Type
+	if name == "" {
+		name = l.Address
+	}
+	factory, ok := r[name]
+	if !ok {
+		return nil, &Error{Kind: KindNotFound, Address: l.Address, Err: fmt.Errorf("no factory for '%s' registered", name)}
+	}
+	return factory(l.Address)
+}
+
+// Build replaces the LayoutTree and the ModelMap with the ones described by the Layout,
+// the Models are made by the factories of the Registry.
+func (b *Boxer) Build(layout Layout, registry Registry) (tea.Cmd, error) {
+	built := Boxer{}
+	root, err := built.build(layout, registry)
+	if err != nil {
+		return nil, err
+	}
+	return b.adopt(root, built)
+}
+
+// adopt replaces the LayoutTree with the root and the Models with the ones of the built Boxer.
+func (b *Boxer) adopt(root Node, built Boxer) (tea.Cmd, error) {
+	b.LayoutTree, b.ModelMap, b.initialized = root, built.ModelMap, built.initialized
+	if !b.LayoutTree.contains(b.focus) {
+		b.focus = ""
+	}
+	return b.relayout()
+}
+
+// build makes the node for the Layout and the Models of its leaves.
+func (b *Boxer) build(l Layout, registry Registry) (Node, error) {
+	if l.Address != "" {
+		if len(l.Children) > 0 {
+			return Node{}, &Error{Kind: KindInvalid, Address: l.Address, Err: fmt.Errorf("a leaf should not have Children")}
+		}
+		model, ok := b.ModelMap[l.Address]
+		if !ok {
+			var err error
+			if model, err = registry.model(l); err != nil {
+				return Node{}, err
+			}
+		}
+		leaf, err := b.CreateLeaf(l.Address, model)
+		if err != nil {
+			return Node{}, err
+		}
+		leaf.Sizing, leaf.Border, leaf.Frame, leaf.Overflow = l.Sizing, l.Border, l.Frame, l.Overflow
+		leaf.factory = l.Type
+		return leaf, nil
+	}
+
+	n := Node{
+		VerticalStacked: l.Vertical,
+		noBorder:        l.NoBorder,
+		Sizing:          l.Sizing,
+		Sizes:           l.Sizes,
+		Border:          l.Border,
+		Frame:           l.Frame,
+		Overflow:        l.Overflow,
+	}
+	if len(l.Rows) > 0 || len(l.Columns) > 0 {
+		n.Grid = &Grid{Rows: l.Rows, Columns: l.Columns}
+	}
+	for i, c := range l.Children {
+		child, err := b.build(c, registry)
+		if err != nil {
+			return Node{}, childError(i, err)
+		}
+		if n.Grid == nil {
+			n.Children = append(n.Children, child)
+			continue
+		}
+		if c.Cell == nil {
+			return Node{}, childError(i, newError(KindInvalid, "a child of a grid needs a cell"))
+		}
+		if err := n.Place(child, *c.Cell); err != nil {
+			return Node{}, childError(i, err)
+		}
+	}
+	return n, nil
+}
+
+// Layout returns the declarative form of the LayoutTree, for example to save it.
+// The leaves keep the Type they were built with (see Build), the others are looked up by there addresses.
+func (b *Boxer) Layout() Layout {
+	return b.LayoutTree.layout(nil)
+}
+
+func (n *Node) layout(cell *Cell) Layout {
+	l := Layout{
+		Address:  n.address,
+		Type:     n.factory,
+		Vertical: n.VerticalStacked,
+		NoBorder: n.noBorder && !n.IsLeaf(),
+		Cell:     cell,
+		Sizing:   n.Sizing,
+		Sizes:    n.Sizes,
+		Border:   n.Border,
+		Frame:    n.Frame,
+		Overflow: n.Overflow,
+	}
+	if n.Grid != nil {
+		l.Rows, l.Columns = n.Grid.Rows, n.Grid.Columns
+	}
+	for i := range n.Children {
+		var childCell *Cell
+		if n.Grid != nil && i < len(n.Grid.Cells) {
+			c := n.Grid.Cells[i]
+			childCell = &c
+		}
+		l.Children = append(l.Children, n.Children[i].layout(childCell))
+	}
+	return l
+}
+
+var alignNames = []string{"left", "center", "right"}
+
+// MarshalText names the Align, so that it is readable in JSON and YAML.
+func (a Align) MarshalText() ([]byte, error) {
+	if a < 0 || int(a) >= len(alignNames) {
+		return nil, fmt.Errorf("unknown align: %d", a)
+	}
+	return []byte(alignNames[a]), nil
+}
+
+// UnmarshalText parses the name of the Align.
+func (a *Align) UnmarshalText(text []byte) error {
+	for i, name := range alignNames {
+		if name == string(text) {
+			*a = Align(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown align: '%s'", text)
+}
+
+var overflowNames = []string{"error", "clip", "wrap", "ellipsis"}
+
+// MarshalText names the Overflow, so that it is readable in JSON and YAML.
+func (o Overflow) MarshalText() ([]byte, error) {
+	if o < 0 || int(o) >= len(overflowNames) {
+		return nil, fmt.Errorf("unknown overflow: %d", o)
+	}
+	return []byte(overflowNames[o]), nil
+}
+
+// UnmarshalText parses the name of the Overflow.
+func (o *Overflow) UnmarshalText(text []byte) error {
+	for i, name := range overflowNames {
+		if name == string(text) {
+			*o = Overflow(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown overflow: '%s'", text)
+}
@@ -0,0 +1,110 @@
+package bubbleboxer
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"gopkg.in/yaml.v3"
+)
+
+func TestLayout(t *testing.T) {
+	config := `
+vertical: true
+children:
+  - address: header
+    type: text
+    sizing: {fixed: 1}
+  - children:
+      - address: left
+        type: text
+        sizing: {percent: 40}
+        overflow: ellipsis
+      - rows: [{}, {}]
+        columns: [{}]
+        noBorder: true
+        frame: {title: grid, titleAlign: center}
+        children:
+          - address: top
+            type: text
+            cell: {row: 0, column: 0}
+          - address: bottom
+            cell: {row: 1, column: 0}
+`
+	var layout Layout
+	if err := yaml.Unmarshal([]byte(config), &layout); err != nil {
+		t.Fatal(err)
+	}
+	registry := Registry{
+		"text":   func(address string) (tea.Model, error) { return testModel(address[:1]), nil },
+		"bottom": func(string) (tea.Model, error) { return testModel("b"), nil },
+	}
+	b := Boxer{}
+	if _, err := b.Build(layout, registry); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.UpdateSize(tea.WindowSizeMsg{Width: 7, Height: 6}); err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Join([]string{
+		"h      ",
+		"──┬────",
+		"l │┌gr┐",
+		"  ││t │",
+		"  ││b │",
+		"  │└──┘",
+	}, NEWLINE)
+	if got := b.View(); got != want {
+		t.Errorf("expected:\n%s\nbut got:\n%s", want, got)
+	}
+	if node, err := b.Find("left"); err != nil || node.Overflow != OverflowEllipsis {
+		t.Errorf("expected the Overflow to be loaded, but got: %v", err)
+	}
+
+	// the layout survives a round trip through JSON and YAML
+	exported := b.Layout()
+	data, err := json.Marshal(exported)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fromJSON Layout
+	if err := json.Unmarshal(data, &fromJSON); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(exported, fromJSON) {
+		t.Errorf("expected the layout to survive JSON:\n%+v\nbut got:\n%+v", exported, fromJSON)
+	}
+	data, err = yaml.Marshal(exported)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fromYAML Layout
+	if err := yaml.Unmarshal(data, &fromYAML); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(exported, fromYAML) {
+		t.Errorf("expected the layout to survive YAML:\n%+v\nbut got:\n%+v", exported, fromYAML)
+	}
+
+	// the exported layout keeps the types and builds the same view
+	rebuilt := Boxer{}
+	if _, err := rebuilt.Build(fromYAML, registry); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rebuilt.UpdateSize(tea.WindowSizeMsg{Width: 7, Height: 6}); err != nil {
+		t.Fatal(err)
+	}
+	if want, got := b.View(), rebuilt.View(); got != want {
+		t.Errorf("expected:\n%s\nbut got:\n%s", want, got)
+	}
+
+	// a missing factory is reported with the address
+	_, err = (&Boxer{}).Build(fromYAML, Registry{})
+	var e *Error
+	if !errors.As(err, &e) || e.Kind != KindNotFound || e.Address != "header" {
+		t.Errorf("expected a KindNotFound error for 'header', but got: %v", err)
+	}
+}
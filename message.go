@@ -0,0 +1,102 @@
+package bubbleboxer
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// AddressedMsg is the envelope of a message which results from a command of a leaf.
+// Boxer.Update unpacks it and delivers the contained Msg to the leaf with the Address,
+// so that the message reaches the Model which issued the command.
+type AddressedMsg struct {
+	Address string
+	Msg     tea.Msg
+}
+
+// programMsgs are the types of the messages of the commands of bubbletea, which control the tea.Program
+// and thus have to reach it unwrapped
+var programMsgs = func() map[reflect.Type]bool {
+	types := make(map[reflect.Type]bool)
+	for _, cmd := range []tea.Cmd{
+		tea.Quit,
+		tea.EnterAltScreen,
+		tea.ExitAltScreen,
+		tea.EnableMouseCellMotion,
+		tea.EnableMouseAllMotion,
+		tea.DisableMouse,
+		tea.HideCursor,
+		tea.ClearScrollArea,
+		tea.SyncScrollArea(nil, 0, 0),
+		tea.ScrollUp(nil, 0, 0),
+		tea.ScrollDown(nil, 0, 0),
+		tea.Exec(nil, nil),
+	} {
+		types[reflect.TypeOf(cmd())] = true
+	}
+	return types
+}()
+
+// cmdType is used to recognize the internal message of tea.Batch
+var cmdType = reflect.TypeOf(tea.Cmd(nil))
+
+// SendTo delivers the msg to the Model of the leaf with the given address.
+func (b *Boxer) SendTo(address string, msg tea.Msg) (tea.Cmd, error) {
+	if _, ok := b.ModelMap[address]; !ok {
+		return nil, &Error{Kind: KindNotFound, Address: address, Err: fmt.Errorf("address '%s' not found", address)}
+	}
+	return b.updateModel(address, msg), nil
+}
+
+// Broadcast delivers the msg to the Models of all leaves.
+func (b *Boxer) Broadcast(msg tea.Msg) tea.Cmd {
+	addresses := make([]string, 0, len(b.ModelMap))
+	for address := range b.ModelMap {
+		addresses = append(addresses, address)
+	}
+	// keep the order of the delivery stable
+	sort.Strings(addresses)
+
+	cmds := make([]tea.Cmd, 0, len(addresses))
+	for _, address := range addresses {
+		cmds = append(cmds, b.updateModel(address, msg))
+	}
+	return tea.Batch(cmds...)
+}
+
+// wrapCmd wraps the cmd of a leaf, so that the resulting message is wrapped in an AddressedMsg.
+// The internal messages of bubbletea (like the one of tea.Quit) are not wrapped
+// and the commands of a tea.Batch are wrapped each on its own.
+func wrapCmd(address string, cmd tea.Cmd) tea.Cmd {
+	if cmd == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		msg := cmd()
+		if msg == nil {
+			return nil
+		}
+
+		value := reflect.ValueOf(msg)
+		if value.Kind() == reflect.Slice && value.Type().Elem() == cmdType {
+			// is batch
+			cmds := make([]tea.Cmd, 0, value.Len())
+			for i := 0; i < value.Len(); i++ {
+				cmds = append(cmds, wrapCmd(address, value.Index(i).Interface().(tea.Cmd)))
+			}
+			batch := tea.Batch(cmds...)
+			if batch == nil {
+				return nil
+			}
+			return batch()
+		}
+
+		if programMsgs[value.Type()] {
+			// is message for the tea.Program
+			return msg
+		}
+		return AddressedMsg{Address: address, Msg: msg}
+	}
+}
@@ -0,0 +1,64 @@
+package bubbleboxer
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// counterModel counts the ints it receives and requests a increment with every tea.KeyMsg
+type counterModel int
+
+func (c counterModel) Init() tea.Cmd { return nil }
+func (c counterModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case int:
+		return c + counterModel(msg), nil
+	case tea.KeyMsg:
+		return c, tea.Batch(func() tea.Msg { return 1 }, tea.Quit)
+	}
+	return c, nil
+}
+func (c counterModel) View() string { return "" }
+
+func TestAddressedMsg(t *testing.T) {
+	b := Boxer{}
+	b.LayoutTree = Node{
+		Children: []Node{
+			stripErr(b.CreateLeaf("a", counterModel(0))),
+			stripErr(b.CreateLeaf("b", counterModel(0))),
+		},
+	}
+	if _, err := b.Focus("a"); err != nil {
+		t.Fatal(err)
+	}
+
+	model, cmd := b.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	msgs := collectMsgs(cmd)
+	if len(msgs) != 2 {
+		t.Fatalf("expected the two messages of the batch but got: %v", msgs)
+	}
+	if msgs[0] != (AddressedMsg{Address: "a", Msg: 1}) {
+		t.Errorf("the message of the leaf should be addressed to it but was: %#v", msgs[0])
+	}
+	if msgs[1] != tea.Quit() {
+		t.Errorf("the internal messages of bubbletea should not be wrapped but got: %#v", msgs[1])
+	}
+
+	model, _ = model.Update(msgs[0])
+	b = model.(Boxer)
+	if b.ModelMap["a"] != counterModel(1) || b.ModelMap["b"] != counterModel(0) {
+		t.Error("the addressed message should only be delivered to the leaf which issued the command")
+	}
+
+	if _, err := b.SendTo("b", 2); err != nil {
+		t.Error(err)
+	}
+	if _, err := b.SendTo("missing", 2); err == nil {
+		t.Error("sending to a missing address should return an error")
+	}
+	b.Broadcast(3)
+	if b.ModelMap["a"] != counterModel(4) || b.ModelMap["b"] != counterModel(5) {
+		t.Errorf("expected a=4 and b=5 but got a=%d and b=%d", b.ModelMap["a"], b.ModelMap["b"])
+	}
+}
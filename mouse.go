@@ -0,0 +1,196 @@
+package bubbleboxer
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// SeparatorMsg is returned (as a tea.Cmd) by Boxer.Update instead of delivering a tea.MouseMsg to a leaf,
+// when the mouse event happened on a separator between two children.
+// The separators of a grid node are reported as well, but unlike the others they can not be dragged.
+type SeparatorMsg struct {
+	// Mouse is the original mouse event with absolute coordinates.
+	Mouse tea.MouseMsg
+
+	// Path holds the indices of the children from the root to the node which draws the separator.
+	Path []int
+
+	// Index is the index of the child before (left of or above) the separator.
+	// It is not used for a grid node.
+	Index int
+
+	// Grid is set if the separator belongs to a grid node, which sizes can only be changed by its Sizing's.
+	// Then Row is the index of the row above and Column the index of the column left of the separator
+	// or -1 if the separator does not run between two rows or columns at this position.
+	Grid   bool
+	Row    int
+	Column int
+}
+
+// drag is a separator which is dragged with the mouse.
+type drag struct {
+	path  []int
+	index int
+}
+
+// LeafAt returns the address of the leaf which is drawn at the absolute position x, y.
+// The returned bool is false if there is no leaf at this position, for example because there is a separator.
+func (b *Boxer) LeafAt(x, y int) (string, bool) {
+	node := b.LayoutTree.at(b.LayoutTree.locate(x, y))
+	if node == nil || !node.IsLeaf() {
+		return "", false
+	}
+	return node.address, true
+}
+
+// routeMouse delivers the mouse event to the leaf under the pointer with coordinates relative to the leaf.
+// If the event happened on a separator a SeparatorMsg is returned instead
+// and if the left button was pressed the separator is dragged till the button is released, unless it belongs to a grid.
+func (b *Boxer) routeMouse(msg tea.MouseMsg) tea.Cmd {
+	if b.drag != nil {
+		switch msg.Type {
+		case tea.MouseLeft, tea.MouseMotion:
+			return b.dragTo(msg.X, msg.Y)
+		case tea.MouseRelease:
+			b.drag = nil
+			return nil
+		}
+		b.drag = nil
+	}
+
+	path := b.LayoutTree.locate(msg.X, msg.Y)
+	node := b.LayoutTree.at(path)
+	if node == nil {
+		return nil
+	}
+
+	if node.IsLeaf() {
+		inner := node.ContentRect()
+		if !inner.Contains(msg.X, msg.Y) {
+			// on the frame of the leaf
+			return nil
+		}
+		local := msg
+		local.X -= inner.X
+		local.Y -= inner.Y
+		return b.updateModel(node.address, local)
+	}
+
+	if node.Grid != nil {
+		row, column, ok := node.gridSeparatorAt(msg.X, msg.Y)
+		if !ok {
+			return nil
+		}
+		sepMsg := SeparatorMsg{Mouse: msg, Path: path, Grid: true, Row: row, Column: column}
+		return func() tea.Msg { return sepMsg }
+	}
+	index, ok := node.separatorAt(msg.X, msg.Y)
+	if !ok {
+		return nil
+	}
+	if msg.Type == tea.MouseLeft {
+		b.drag = &drag{path: path, index: index}
+	}
+	sepMsg := SeparatorMsg{Mouse: msg, Path: path, Index: index}
+	return func() tea.Msg { return sepMsg }
+}
+
+// dragTo moves the dragged separator to the absolute position x, y.
+func (b *Boxer) dragTo(x, y int) tea.Cmd {
+	n := b.LayoutTree.at(b.drag.path)
+	if n == nil || b.drag.index >= len(n.Children)-1 {
+		// the layout-tree changed while dragging
+		b.drag = nil
+		return nil
+	}
+	c := n.Children[b.drag.index]
+	delta := x - (c.x + c.width)
+	if n.VerticalStacked {
+		delta = y - (c.y + c.height)
+	}
+	cmd, _ := b.MoveSeparator(b.drag.path, b.drag.index, delta)
+	return cmd
+}
+
+// locate returns the path to the deepest node which covers the absolute position x, y.
+// If the position is outside of this node nil is returned.
+func (n *Node) locate(x, y int) []int {
+	if !n.covers(x, y) {
+		return nil
+	}
+	path := []int{}
+	for i := range n.Children {
+		if sub := n.Children[i].locate(x, y); sub != nil {
+			return append(append(path, i), sub...)
+		}
+	}
+	return path
+}
+
+// at returns the node which is reached by following the path from this node on
+// or nil if the path does not exist.
+func (n *Node) at(path []int) *Node {
+	if path == nil {
+		return nil
+	}
+	node := n
+	for _, i := range path {
+		if i < 0 || i >= len(node.Children) {
+			return nil
+		}
+		node = &node.Children[i]
+	}
+	return node
+}
+
+// covers returns if the absolute position x, y is within this node.
+func (n *Node) covers(x, y int) bool {
+	return n.Rect().Contains(x, y)
+}
+
+// separatorAt returns the index of the child before the separator which is drawn at the absolute position x, y.
+func (n *Node) separatorAt(x, y int) (int, bool) {
+	if n.noBorder || n.Grid != nil || !n.covers(x, y) {
+		return 0, false
+	}
+	for i := 0; i < len(n.Children)-1; i++ {
+		c := n.Children[i]
+		if n.VerticalStacked && y == c.y+c.height {
+			return i, true
+		}
+		if !n.VerticalStacked && x == c.x+c.width {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// gridSeparatorAt returns the indices of the row above and the column left of the separator of the grid
+// which is drawn at the absolute position x, y. The one which the separator does not run between is -1.
+func (n *Node) gridSeparatorAt(x, y int) (int, int, bool) {
+	if n.noBorder || n.Grid == nil || !n.covers(x, y) || n.childAt(x, y) >= 0 {
+		return 0, 0, false
+	}
+	border := n.borderWidth()
+	inner := n.ContentRect()
+	// band returns the track before the separator at the position or -1 if the position is on a track
+	band := func(tracks []int, start, position int) (int, bool) {
+		for _, b := range bands(tracks, border) {
+			if position >= start+b.start && position < start+b.start+b.size {
+				if b.separator {
+					return b.track - 1, true
+				}
+				return -1, true
+			}
+		}
+		return -1, false
+	}
+	row, inRows := band(n.gridRows, inner.Y, y)
+	column, inColumns := band(n.gridColumns, inner.X, x)
+	if !inRows || !inColumns {
+		return 0, 0, false
+	}
+	if row < 0 && column < 0 {
+		return 0, 0, false
+	}
+	return row, column, true
+}
@@ -0,0 +1,84 @@
+package bubbleboxer
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// mouseModel remembers the last mouse event it received
+type mouseModel struct {
+	last *tea.MouseMsg
+}
+
+func (m mouseModel) Init() tea.Cmd { return nil }
+func (m mouseModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.MouseMsg); ok {
+		m.last = &msg
+	}
+	return m, nil
+}
+func (m mouseModel) View() string { return "" }
+
+func TestMouseRouting(t *testing.T) {
+	b := Boxer{}
+	b.LayoutTree = Node{
+		Children: []Node{
+			stripErr(b.CreateLeaf("left", mouseModel{})),
+			{
+				VerticalStacked: true,
+				Children: []Node{
+					stripErr(b.CreateLeaf("upper", mouseModel{})),
+					stripErr(b.CreateLeaf("lower", mouseModel{})),
+				},
+			},
+		},
+	}
+	// left is 5 wide, the separator is on x=5 and the right node starts at x=6
+	// upper is 2 high, the separator is on y=2 and lower starts at y=3
+	if _, err := b.UpdateSize(tea.WindowSizeMsg{Width: 11, Height: 5}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range []struct {
+		x, y    int
+		address string
+		ok      bool
+	}{
+		{0, 0, "left", true},
+		{4, 4, "left", true},
+		{5, 0, "", false},
+		{6, 0, "upper", true},
+		{8, 2, "", false},
+		{10, 4, "lower", true},
+		{11, 4, "", false},
+	} {
+		address, ok := b.LeafAt(c.x, c.y)
+		if address != c.address || ok != c.ok {
+			t.Errorf("expected (%s, %t) at %d,%d but got (%s, %t)", c.address, c.ok, c.x, c.y, address, ok)
+		}
+	}
+
+	model, _ := b.Update(tea.MouseMsg{X: 7, Y: 4, Type: tea.MouseLeft})
+	b = model.(Boxer)
+	last := b.ModelMap["lower"].(mouseModel).last
+	if last == nil {
+		t.Fatal("the leaf under the pointer should have received the mouse event")
+	}
+	if last.X != 1 || last.Y != 1 {
+		t.Errorf("the mouse event should be relative to the leaf and thus at 1,1 but was at %d,%d", last.X, last.Y)
+	}
+
+	_, cmd := b.Update(tea.MouseMsg{X: 8, Y: 2, Type: tea.MouseLeft})
+	msgs := collectMsgs(cmd)
+	if len(msgs) != 1 {
+		t.Fatal("a click on a separator should be reported")
+	}
+	sep, ok := msgs[0].(SeparatorMsg)
+	if !ok {
+		t.Fatal("a click on a separator should be reported as SeparatorMsg")
+	}
+	if len(sep.Path) != 1 || sep.Path[0] != 1 || sep.Index != 0 {
+		t.Errorf("expected the separator after the first child of the node at [1] but got index %d of %v", sep.Index, sep.Path)
+	}
+}
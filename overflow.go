@@ -0,0 +1,102 @@
+package bubbleboxer
+
+import (
+	"fmt"
+)
+
+// Overflow decides what happens if the View of a Model has more lines or wider lines than the leaf has space for.
+type Overflow int
+
+const (
+	// OverflowError makes the rendering fail, which replaces the whole View of the Boxer with the error.
+	OverflowError Overflow = iota
+	// OverflowClip cuts off the lines and columns which do not fit.
+	OverflowClip
+	// OverflowWrap breaks too wide lines at the spaces (or within a word if it is too long)
+	// and cuts off the lines which do not fit afterwards.
+	OverflowWrap
+	// OverflowEllipsis cuts off what does not fit and marks the cut with Ellipsis.
+	OverflowEllipsis
+)
+
+// Ellipsis marks where a line was cut off by OverflowEllipsis.
+var Ellipsis = "…"
+
+// fit applies the Overflow policy of the leaf to the lines of its Model so that they fit into width and height.
+// Tabs are expanded before.
+func (n *Node) fit(m measure, lines []string, width, height int) ([]string, error) {
+	for i, line := range lines {
+		lines[i] = m.expandTabs(line)
+	}
+	if n.Overflow != OverflowError {
+		return n.Overflow.apply(m, lines, width, height), nil
+	}
+	if len(lines) > height {
+		return lines, n.overflowError(len(lines), 0)
+	}
+	for _, line := range lines {
+		if lineWidth := m.lineWidth(line); lineWidth > width {
+			return lines, n.overflowError(0, lineWidth)
+		}
+	}
+	return lines, nil
+}
+
+// overflowError returns a error of the KindOverflow for the node, which content has to much lines or a too wide line.
+// The dimension which did not overflow is zero.
+func (n *Node) overflowError(lines, lineWidth int) *Error {
+	inner := n.ContentRect()
+	e := &Error{Kind: KindOverflow, Address: n.address}
+	if lines > 0 {
+		e.Expected.Height, e.Actual.Height = inner.Height, lines
+		e.Err = fmt.Errorf("expecting less or equal to %d lines, but got %d", inner.Height, lines)
+		return e
+	}
+	e.Expected.Width, e.Actual.Width = inner.Width, lineWidth
+	e.Err = fmt.Errorf("expecting less or equal to %d character width of all lines, but got a line with %d characters", inner.Width, lineWidth)
+	return e
+}
+
+// apply cuts or wraps the lines so that they fit into width and height.
+// OverflowError is treated like OverflowClip.
+func (o Overflow) apply(m measure, lines []string, width, height int) []string {
+	switch o {
+	case OverflowWrap:
+		var wrapped []string
+		for _, line := range lines {
+			for _, part := range m.wrap(line, width) {
+				// a single character can still be wider than width
+				wrapped = append(wrapped, m.clip(part, width, ""))
+			}
+		}
+		return clipLines(wrapped, height)
+	case OverflowEllipsis:
+		cut := len(lines) > height
+		lines = clipLines(lines, height)
+		for i, line := range lines {
+			lines[i] = m.clip(line, width, Ellipsis)
+		}
+		if cut && height > 0 {
+			// mark the missing lines at the end of the last visible line
+			last := lines[height-1]
+			if m.lineWidth(last)+m.lineWidth(Ellipsis) > width {
+				last = m.clip(last, width-m.lineWidth(Ellipsis), "")
+			}
+			lines[height-1] = last + Ellipsis
+		}
+		return lines
+	}
+	lines = clipLines(lines, height)
+	for i, line := range lines {
+		lines[i] = m.clip(line, width, "")
+	}
+	return lines
+}
+
+// clipLines returns at most height lines.
+func clipLines(lines []string, height int) []string {
+	if len(lines) > height {
+		return lines[:height]
+	}
+	return lines
+}
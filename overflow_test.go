@@ -0,0 +1,40 @@
+package bubbleboxer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOverflow(t *testing.T) {
+	lines := []string{"hello world", "ab", "cd"}
+	for _, test := range []struct {
+		overflow Overflow
+		want     []string
+	}{
+		{OverflowClip, []string{"hello", "ab"}},
+		{OverflowWrap, []string{"hello", "world"}},
+		{OverflowEllipsis, []string{"hell…", "ab…"}},
+	} {
+		n := Node{Overflow: test.overflow}
+		got, err := n.fit(defaultMeasure, append([]string{}, lines...), 5, 2)
+		if err != nil {
+			t.Errorf("overflow %d should not return an error but did: %s", test.overflow, err)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("overflow %d: expected %q but got %q", test.overflow, test.want, got)
+		}
+	}
+
+	n := Node{}
+	if _, err := n.fit(defaultMeasure, append([]string{}, lines...), 5, 3); err == nil {
+		t.Error("expected an error for a too wide line by default")
+	}
+	if _, err := n.fit(defaultMeasure, []string{"ab", "cd"}, 5, 1); err == nil {
+		t.Error("expected an error for too much lines by default")
+	}
+
+	clipped, _ := (&Node{Overflow: OverflowClip}).fit(defaultMeasure, []string{"\x1b[31mhello\x1b[0m"}, 2, 1)
+	if got := clipped[0]; got != "\x1b[31mhe\x1b[0m" {
+		t.Errorf("expected the escape sequences to be kept while clipping, but got %q", got)
+	}
+}
@@ -0,0 +1,241 @@
+package bubbleboxer
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// MoveSeparator moves the separator after the child with the index of the node at the path by delta cells,
+// as far as the children next to it keep there minimal size. The new sizes are kept in the Sizes of the node.
+// The separators of a grid node can not be moved, for them a error of the KindInvalid is returned.
+func (b *Boxer) MoveSeparator(path []int, index, delta int) (tea.Cmd, error) {
+	n := b.LayoutTree.at(path)
+	if n == nil {
+		return nil, &Error{Kind: KindNotFound, Path: path, Err: fmt.Errorf("no node at the path")}
+	}
+	if n.IsLeaf() || n.Grid != nil || index < 0 || index >= len(n.Children)-1 {
+		return nil, &Error{Kind: KindInvalid, Path: path, Err: fmt.Errorf("the node has no separator after the child %d", index)}
+	}
+
+	sizes := n.childSizes()
+	before, after := &n.Children[index], &n.Children[index+1]
+	// the room is the amount of cells the child, which shrinks, can give away
+	room := sizes[index] - before.minSizeAlong(n.VerticalStacked)
+	if delta > 0 {
+		room = sizes[index+1] - after.minSizeAlong(n.VerticalStacked)
+	}
+	if room < 0 {
+		room = 0
+	}
+	if delta > room {
+		delta = room
+	} else if -delta > room {
+		delta = -room
+	}
+	if delta == 0 {
+		return nil, nil
+	}
+	sizes[index] += delta
+	sizes[index+1] -= delta
+	previous := n.Sizes
+	n.Sizes = sizes
+	cmd, err := b.relayout()
+	if err != nil {
+		// keep the layout which fitted
+		n.Sizes = previous
+		restoreCmd, _ := b.relayout()
+		return tea.Batch(cmd, restoreCmd), err
+	}
+	return cmd, nil
+}
+
+// ResizeFocused grows (or shrinks if negative) the focused leaf by dx and dy cells,
+// within the nearest ancestor of each orientation at the cost of the next (or else the previous) sibling.
+func (b *Boxer) ResizeFocused(dx, dy int) (tea.Cmd, error) {
+	path, err := b.leafPath(b.focus)
+	if err != nil {
+		return nil, err
+	}
+	var cmds []tea.Cmd
+	for _, axis := range []struct {
+		delta    int
+		vertical bool
+	}{{dx, false}, {dy, true}} {
+		if axis.delta == 0 {
+			continue
+		}
+		cmd, err := b.resizeAlong(path, axis.delta, axis.vertical)
+		cmds = append(cmds, cmd)
+		if err != nil {
+			return tea.Batch(cmds...), err
+		}
+	}
+	return tea.Batch(cmds...), nil
+}
+
+// resizeAlong grows the node at the path by delta cells within the nearest ancestor with the orientation.
+func (b *Boxer) resizeAlong(path []int, delta int, vertical bool) (tea.Cmd, error) {
+	for depth := len(path) - 1; depth >= 0; depth-- {
+		parentPath, index := path[:depth], path[depth]
+		parent := b.LayoutTree.at(parentPath)
+		if parent.Grid != nil || parent.VerticalStacked != vertical || len(parent.Children) < 2 {
+			continue
+		}
+		if index < len(parent.Children)-1 {
+			return b.MoveSeparator(parentPath, index, delta)
+		}
+		return b.MoveSeparator(parentPath, index-1, -delta)
+	}
+	direction := "side by side"
+	if vertical {
+		direction = "on top of each other"
+	}
+	return nil, &Error{Kind: KindInvalid, Address: b.focus, Err: fmt.Errorf("no ancestor arranges its children %s, so the leaf can not be resized in this direction", direction)}
+}
+
+// ResetSizes removes the Sizes of all nodes, so that the SizeFunc's and Sizing's are used again.
+func (b *Boxer) ResetSizes() (tea.Cmd, error) {
+	b.LayoutTree.walk(func(n *Node) {
+		n.Sizes = nil
+	})
+	return b.relayout()
+}
+
+// childSizes returns the current sizes of the children along the orientation of the node.
+func (n *Node) childSizes() []int {
+	sizes := make([]int, len(n.Children))
+	for i, c := range n.Children {
+		sizes[i] = c.width
+		if n.VerticalStacked {
+			sizes[i] = c.height
+		}
+	}
+	return sizes
+}
+
+// minSize returns the smallest height (if vertical) or width the node can be shrunken to by moving a separator,
+// so that its frame, its separators and each of its descendants still get a cell.
+func (n *Node) minSize(vertical bool) int {
+	var content int
+	switch {
+	case n.IsLeaf():
+		content = 1
+	case n.Grid != nil:
+		tracks := n.Grid.Columns
+		if vertical {
+			tracks = n.Grid.Rows
+		}
+		content = n.borderWidth() * (len(tracks) - 1)
+		for _, t := range tracks {
+			content += t.lowest()
+		}
+	case n.VerticalStacked == vertical:
+		// the children are arranged along this dimension
+		content = n.borderWidth() * (len(n.Children) - 1)
+		for i := range n.Children {
+			content += n.Children[i].minSizeAlong(vertical)
+		}
+	default:
+		for i := range n.Children {
+			if size := n.Children[i].minSize(vertical); size > content {
+				content = size
+			}
+		}
+	}
+	return content + 2*n.frameWidth()
+}
+
+// minSizeAlong is the minSize of a node whose parent arranges its children along the same dimension,
+// so that the Min of its Sizing applies as well.
+func (n *Node) minSizeAlong(vertical bool) int {
+	if size := n.minSize(vertical); size > n.Sizing.Min {
+		return size
+	}
+	return n.Sizing.Min
+}
+
+// neededSize is the size of the node, enlarged to its minSize in the dimensions where it is smaller.
+func (n *Node) neededSize() Size {
+	needed := Size{Width: n.width, Height: n.height}
+	if size := n.minSize(false); size > needed.Width {
+		needed.Width = size
+	}
+	if size := n.minSize(true); size > needed.Height {
+		needed.Height = size
+	}
+	return needed
+}
+
+// tooSmall fills in the size of the node and the size it needs into a KindTooSmall error, which occurred in the node.
+func (n *Node) tooSmall(err error) error {
+	var e *Error
+	if errors.As(err, &e) && e.Kind == KindTooSmall && e.Actual == (Size{}) {
+		e.Expected = Size{Width: n.width, Height: n.height}
+		e.Actual = n.neededSize()
+	}
+	return err
+}
+
+// splitSize divides the entry of the Sizes for the child at the index between this child,
+// which keeps the ratio of the space without the new separator, and a new child placed behind it if offset is 1 or in front of it if offset is 0.
+// It has to be called before the new child is added and does nothing if the Sizes are not used.
+func (n *Node) splitSize(index, offset int, ratio float64) {
+	if len(n.Sizes) != len(n.Children) {
+		return
+	}
+	space := n.Sizes[index] - n.borderWidth()
+	keep := int(math.Round(float64(space) * ratio))
+	if keep < 1 {
+		keep = 1
+	}
+	give := space - keep
+	if give < 1 {
+		give = 1
+	}
+	pair := []int{keep, give}
+	if offset == 0 {
+		pair = []int{give, keep}
+	}
+	sizes := make([]int, 0, len(n.Sizes)+1)
+	sizes = append(sizes, n.Sizes[:index]...)
+	sizes = append(sizes, pair...)
+	n.Sizes = append(sizes, n.Sizes[index+1:]...)
+}
+
+// removeSize removes the entry of the Sizes for the child at the index and gives its space to the sibling before it
+// or if there is none to the one after it. It has to be called before the child is removed.
+func (n *Node) removeSize(index int) {
+	if len(n.Sizes) != len(n.Children) {
+		return
+	}
+	sizes := make([]int, 0, len(n.Sizes)-1)
+	sizes = append(sizes, n.Sizes[:index]...)
+	sizes = append(sizes, n.Sizes[index+1:]...)
+	if len(sizes) > 0 {
+		neighbour := index - 1
+		if neighbour < 0 {
+			neighbour = 0
+		}
+		sizes[neighbour] += n.Sizes[index] + n.borderWidth()
+	}
+	n.Sizes = sizes
+}
+
+// scaledSizes returns the Sizes scaled to the available space, the minimal sizes of the children are kept.
+func (n *Node) scaledSizes(available int) ([]int, error) {
+	var sum int
+	for _, size := range n.Sizes {
+		sum += size
+	}
+	if sum == available {
+		return append([]int{}, n.Sizes...), nil
+	}
+	specs := make([]Sizing, len(n.Sizes))
+	for i, size := range n.Sizes {
+		specs[i] = Sizing{Weight: float64(size), Min: n.Children[i].minSizeAlong(n.VerticalStacked)}
+	}
+	return resolveSizes(specs, available)
+}
@@ -0,0 +1,154 @@
+package bubbleboxer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestDragSeparator(t *testing.T) {
+	b := Boxer{}
+	b.LayoutTree = Node{
+		Children: []Node{
+			stripErr(b.CreateLeaf("left", mouseModel{})),
+			stripErr(b.CreateLeaf("right", mouseModel{})),
+		},
+	}
+	b.LayoutTree.Children[1].Sizing.Min = 3
+	if _, err := b.UpdateSize(tea.WindowSizeMsg{Width: 11, Height: 2}); err != nil {
+		t.Fatal(err)
+	}
+	send := func(x int, typ tea.MouseEventType) {
+		model, _ := b.Update(tea.MouseMsg{X: x, Y: 1, Type: typ})
+		b = model.(Boxer)
+	}
+	widths := func(want ...int) {
+		t.Helper()
+		if got := b.LayoutTree.childSizes(); !reflect.DeepEqual(got, want) {
+			t.Errorf("expected the widths %v but got %v", want, got)
+		}
+	}
+
+	// press on the separator and drag it to the right
+	send(5, tea.MouseLeft)
+	send(6, tea.MouseLeft)
+	send(7, tea.MouseMotion)
+	widths(7, 3)
+	// the Min of the right child is respected
+	send(9, tea.MouseLeft)
+	widths(7, 3)
+	send(2, tea.MouseLeft)
+	widths(2, 8)
+	send(2, tea.MouseRelease)
+
+	// after the release the mouse events reach the leaves again
+	send(4, tea.MouseLeft)
+	widths(2, 8)
+	if b.ModelMap["right"].(mouseModel).last == nil {
+		t.Error("expected the mouse event to be delivered to the leaf after the separator was released")
+	}
+
+	// the sizes are kept on the node and are scaled with the available space
+	if !reflect.DeepEqual(b.LayoutTree.Sizes, []int{2, 8}) {
+		t.Errorf("expected the sizes to be kept on the node, but got %v", b.LayoutTree.Sizes)
+	}
+	if _, err := b.UpdateSize(tea.WindowSizeMsg{Width: 21, Height: 2}); err != nil {
+		t.Fatal(err)
+	}
+	widths(4, 16)
+
+	if _, err := b.ResetSizes(); err != nil {
+		t.Fatal(err)
+	}
+	widths(10, 10)
+}
+
+func TestResizeFocused(t *testing.T) {
+	b := Boxer{}
+	var err error
+	b.LayoutTree, err = b.Parse("v(top, h(left, right))", map[string]tea.Model{
+		"top":   testModel("t"),
+		"left":  testModel("l"),
+		"right": testModel("r"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.UpdateSize(tea.WindowSizeMsg{Width: 11, Height: 5}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.ResizeFocused(1, 0); err == nil {
+		t.Error("expected a error without a focused leaf")
+	}
+	b.Focus("right")
+
+	// the right leaf has no next sibling, so it takes the space from the left leaf
+	if _, err := b.ResizeFocused(2, 1); err != nil {
+		t.Fatal(err)
+	}
+	if got := b.LayoutTree.Children[1].childSizes(); !reflect.DeepEqual(got, []int{3, 7}) {
+		t.Errorf("expected the widths [3 7] but got %v", got)
+	}
+	if got := b.LayoutTree.childSizes(); !reflect.DeepEqual(got, []int{1, 3}) {
+		t.Errorf("expected the heights [1 3] but got %v", got)
+	}
+
+	// shrinking stops at the minimal size
+	b.Focus("left")
+	if _, err := b.ResizeFocused(-5, 0); err != nil {
+		t.Fatal(err)
+	}
+	if got := b.LayoutTree.Children[1].childSizes(); !reflect.DeepEqual(got, []int{1, 9}) {
+		t.Errorf("expected the widths [1 9] but got %v", got)
+	}
+
+	if _, err := b.ResetSizes(); err != nil {
+		t.Fatal(err)
+	}
+	if got := b.LayoutTree.Children[1].childSizes(); !reflect.DeepEqual(got, []int{5, 5}) {
+		t.Errorf("expected the declared widths [5 5] after the reset but got %v", got)
+	}
+}
+
+func TestMoveSeparator(t *testing.T) {
+	b := Boxer{}
+	models := map[string]tea.Model{}
+	for _, address := range []string{"a", "b", "c", "d", "e"} {
+		models[address] = testModel(address)
+	}
+	var err error
+	b.LayoutTree, err = b.Parse("h(a, h(b, c, d))", models)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.UpdateSize(tea.WindowSizeMsg{Width: 30, Height: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	// the nested node keeps a cell for each of its leaves and its separators
+	if _, err := b.MoveSeparator([]int{}, 0, 100); err != nil {
+		t.Fatal(err)
+	}
+	if got := b.LayoutTree.childSizes(); !reflect.DeepEqual(got, []int{24, 5}) {
+		t.Errorf("expected the widths [24 5] but got %v", got)
+	}
+	if view := b.View(); strings.Contains(view, "error") {
+		t.Errorf("expected the layout to be rendered but got: %s", view)
+	}
+
+	// the sizes are kept while siblings are added and removed
+	if _, err := b.InsertAfter("a", stripErr(b.CreateLeaf("e", models["e"]))); err != nil {
+		t.Fatal(err)
+	}
+	if got := b.LayoutTree.childSizes(); !reflect.DeepEqual(got, []int{12, 11, 5}) {
+		t.Errorf("expected the widths [12 11 5] but got %v", got)
+	}
+	if _, err := b.Remove("e"); err != nil {
+		t.Fatal(err)
+	}
+	if got := b.LayoutTree.childSizes(); !reflect.DeepEqual(got, []int{24, 5}) {
+		t.Errorf("expected the widths [24 5] but got %v", got)
+	}
+}
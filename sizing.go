@@ -0,0 +1,220 @@
+package bubbleboxer
+
+import (
+	"math"
+	"sort"
+)
+
+// Sizing declares how much space a child gets along the orientation of its parent.
+// The zero value is a flexible child with the Weight 1,
+// so that children without a Sizing share the space evenly.
+type Sizing struct {
+	// Fixed is the amount of cells the child gets, it is used if it is greater than zero.
+	Fixed int `json:"fixed,omitempty" yaml:"fixed,omitempty"`
+
+	// Percent is the share of the available space in percent,
+	// it is used if it is greater than zero and Fixed is not set.
+	Percent float64 `json:"percent,omitempty" yaml:"percent,omitempty"`
+
+	// Weight is the share of the space which is left after the fixed and percentage children got their space,
+	// relative to the weights of the other flexible children. A Weight of zero or less is treated as 1.
+	Weight float64 `json:"weight,omitempty" yaml:"weight,omitempty"`
+
+	// Min and Max limit the size of the child, they are ignored if they are zero.
+	Min int `json:"min,omitempty" yaml:"min,omitempty"`
+	Max int `json:"max,omitempty" yaml:"max,omitempty"`
+}
+
+// Cells is a shortcut for a Sizing with a fixed amount of cells.
+func Cells(cells int) Sizing { return Sizing{Fixed: cells} }
+
+// Percent is a shortcut for a Sizing with a percentage of the available space.
+func Percent(percent float64) Sizing { return Sizing{Percent: percent} }
+
+// Weight is a shortcut for a flexible Sizing with the given weight.
+func Weight(weight float64) Sizing { return Sizing{Weight: weight} }
+
+// isFlexible returns if the size is determined by the Weight.
+func (s Sizing) isFlexible() bool {
+	return s.Fixed <= 0 && s.Percent <= 0
+}
+
+func (s Sizing) weight() float64 {
+	if s.Weight <= 0 {
+		return 1
+	}
+	return s.Weight
+}
+
+// clamp limits the size to Min and Max.
+func (s Sizing) clamp(size float64) float64 {
+	if s.Max > 0 && size > float64(s.Max) {
+		size = float64(s.Max)
+	}
+	if s.Min > 0 && size < float64(s.Min) {
+		size = float64(s.Min)
+	}
+	return size
+}
+
+// lowest returns the size the child keeps at least, while the space is shrunken.
+func (s Sizing) lowest() int {
+	if s.Min > 1 {
+		return s.Min
+	}
+	return 1
+}
+
+// resolveSizes distributes the available space according to the specs.
+// First the fixed and percentage sizes are served, the rest is shared between the flexible sizes according to there weight.
+// The division remainder goes to the sizes with the biggest fractional part and on equality to the first ones.
+// Each flexible size keeps room for at least its Min or one cell, for which the fixed and percentage sizes are shrunken.
+// If the sizes demand more than available they are shrunken from the last to the first one,
+// first down to there Min and if that is not enough down to a size of one.
+// If the sizes demand less than available (for example because of a Max) the space is left empty.
+func resolveSizes(specs []Sizing, available int) ([]int, error) {
+	sizes := make([]int, len(specs))
+
+	// serve the fixed and percentage sizes first
+	var flexible, served []int
+	shares := make(map[int]float64)
+	var total float64
+	reserved := 0
+	// the flexible sizes are not shrunken below one cell while sharing the space
+	flexibleSpecs := append([]Sizing{}, specs...)
+	for i, s := range specs {
+		if s.isFlexible() {
+			flexible = append(flexible, i)
+			flexibleSpecs[i].Min = s.lowest()
+			reserved += s.lowest()
+			continue
+		}
+		served = append(served, i)
+		size := float64(s.Fixed)
+		if s.Fixed <= 0 {
+			size = float64(available) * s.Percent / 100
+		}
+		shares[i] = s.clamp(size)
+		total += shares[i]
+	}
+	distribute(shares, sizes, served, int(math.Round(total)))
+
+	// leave room for the flexible sizes
+	rest := available
+	for _, i := range served {
+		rest -= sizes[i]
+	}
+	if lacking := reserved - rest; lacking > 0 {
+		rest += lacking - shrink(specs, sizes, served, lacking)
+	}
+
+	shareFlexible(flexibleSpecs, sizes, flexible, rest)
+
+	// shrink if the sizes demand more space than available
+	var sum int
+	for _, size := range sizes {
+		sum += size
+	}
+	all := make([]int, len(sizes))
+	for i := range all {
+		all[i] = i
+	}
+	if excess := shrink(specs, sizes, all, sum-available); excess > 0 {
+		return sizes, newError(KindTooSmall, "not enough space for %d children: %d cells are available but %d more are needed", len(specs), available, excess)
+	}
+	return sizes, nil
+}
+
+// shrink reduces the sizes with the indices by the excess, from the last to the first one,
+// first down to there Min and if that is not enough down to a size of one.
+// It returns the excess which could not be removed.
+func shrink(specs []Sizing, sizes []int, indices []int, excess int) int {
+	for _, ignoreMin := range []bool{false, true} {
+		for j := len(indices) - 1; j >= 0 && excess > 0; j-- {
+			i := indices[j]
+			lowest := 1
+			if !ignoreMin {
+				lowest = specs[i].lowest()
+			}
+			if reduce := sizes[i] - lowest; reduce > 0 {
+				if reduce > excess {
+					reduce = excess
+				}
+				sizes[i] -= reduce
+				excess -= reduce
+			}
+		}
+	}
+	if excess < 0 {
+		return 0
+	}
+	return excess
+}
+
+// distribute sets the sizes with the indices to the floor of there shares
+// and hands out the rest of the total to the sizes with the biggest fractional part and on equality to the first ones.
+func distribute(shares map[int]float64, sizes []int, indices []int, total int) {
+	for _, i := range indices {
+		sizes[i] = int(math.Floor(shares[i]))
+		total -= sizes[i]
+	}
+	byFraction := append([]int{}, indices...)
+	sort.SliceStable(byFraction, func(a, b int) bool {
+		fa := shares[byFraction[a]] - math.Floor(shares[byFraction[a]])
+		fb := shares[byFraction[b]] - math.Floor(shares[byFraction[b]])
+		return fa > fb+1e-9
+	})
+	for _, i := range byFraction {
+		if total <= 0 {
+			break
+		}
+		sizes[i]++
+		total--
+	}
+}
+
+// shareFlexible shares the rest of the space between the flexible sizes according to there weights and limits.
+func shareFlexible(specs []Sizing, sizes []int, flexible []int, rest int) {
+	active := flexible
+	for len(active) > 0 {
+		if rest <= 0 {
+			for _, i := range active {
+				sizes[i] = int(specs[i].clamp(0))
+			}
+			return
+		}
+
+		var weightSum float64
+		for _, i := range active {
+			weightSum += specs[i].weight()
+		}
+		shares := make(map[int]float64, len(active))
+		var violation float64
+		for _, i := range active {
+			shares[i] = float64(rest) * specs[i].weight() / weightSum
+			violation += specs[i].clamp(shares[i]) - shares[i]
+		}
+
+		// freeze the sizes which violate there limits and share the rest again between the others
+		var next []int
+		for _, i := range active {
+			clamped := specs[i].clamp(shares[i])
+			if clamped == shares[i] ||
+				(violation > 0 && clamped < shares[i]) ||
+				(violation < 0 && clamped > shares[i]) {
+				next = append(next, i)
+				continue
+			}
+			sizes[i] = int(clamped)
+			rest -= sizes[i]
+		}
+		if len(next) < len(active) {
+			active = next
+			continue
+		}
+
+		// no violations, so distribute the shares and the division remainder
+		distribute(shares, sizes, active, rest)
+		return
+	}
+}
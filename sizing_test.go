@@ -0,0 +1,82 @@
+package bubbleboxer
+
+import (
+	"reflect"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestResolveSizes(t *testing.T) {
+	for _, c := range []struct {
+		name      string
+		specs     []Sizing
+		available int
+		want      []int
+		fails     bool
+	}{
+		{"even", []Sizing{{}, {}, {}}, 11, []int{4, 4, 3}, false},
+		{"fixed and flexible", []Sizing{Cells(1), {}, Cells(1)}, 10, []int{1, 8, 1}, false},
+		{"percent", []Sizing{Percent(20), {}, Cells(30)}, 100, []int{20, 50, 30}, false},
+		{"weights", []Sizing{Weight(1), Weight(2)}, 10, []int{3, 7}, false},
+		{"max", []Sizing{{Max: 2}, {}, {}}, 10, []int{2, 4, 4}, false},
+		{"min", []Sizing{{Min: 6}, {}, {}}, 10, []int{6, 2, 2}, false},
+		{"all limited leaves space empty", []Sizing{{Max: 2}, {Max: 3}}, 10, []int{2, 3}, false},
+		{"shrink to min", []Sizing{Cells(6), {Fixed: 6, Min: 2}}, 10, []int{6, 4}, false},
+		{"shrink below min", []Sizing{{Fixed: 6, Min: 5}, {Fixed: 6, Min: 5}}, 8, []int{5, 3}, false},
+		{"percent remainder", []Sizing{Percent(50), Percent(50)}, 9, []int{5, 4}, false},
+		{"percent thirds", []Sizing{Percent(33.33), Percent(33.33), Percent(33.33)}, 100, []int{34, 33, 33}, false},
+		{"room for flexible", []Sizing{Cells(20), {}}, 20, []int{19, 1}, false},
+		{"room for flexible min", []Sizing{Percent(100), {Min: 3}, {}}, 10, []int{6, 3, 1}, false},
+		{"not enough space", []Sizing{Cells(3), Cells(3)}, 1, nil, true},
+	} {
+		got, err := resolveSizes(c.specs, c.available)
+		if c.fails {
+			if err == nil {
+				t.Errorf("%s: expected an error but got %v", c.name, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %s", c.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s: expected %v but got %v", c.name, c.want, got)
+		}
+	}
+}
+
+func TestSizingInTree(t *testing.T) {
+	b := Boxer{}
+	header := stripErr(b.CreateLeaf("header", testModel("header")))
+	header.Sizing = Cells(1)
+	main := stripErr(b.CreateLeaf("main", testModel("main")))
+	b.LayoutTree = Node{
+		VerticalStacked: true,
+		Children:        []Node{header, main},
+	}
+	if _, err := b.UpdateSize(tea.WindowSizeMsg{Width: 10, Height: 10}); err != nil {
+		t.Fatal(err)
+	}
+	if h := b.LayoutTree.Children[0].GetHeight(); h != 1 {
+		t.Errorf("the header should be one line high but is %d", h)
+	}
+	// the rest without the separator
+	if h := b.LayoutTree.Children[1].GetHeight(); h != 8 {
+		t.Errorf("the main leaf should get the rest of 8 lines but got %d", h)
+	}
+
+	// a flexible leaf which is pushed out by a fixed sibling keeps a cell
+	var err error
+	b.LayoutTree, err = b.Parse("h(a:20, b)", map[string]tea.Model{"a": testModel("a"), "b": testModel("b")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.UpdateSize(tea.WindowSizeMsg{Width: 21, Height: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if w := b.LayoutTree.Children[1].GetWidth(); w != 1 {
+		t.Errorf("the flexible leaf should keep one cell but got %d", w)
+	}
+}
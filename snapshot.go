@@ -0,0 +1,128 @@
+package bubbleboxer
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// SnapshotVersion is the version of the snapshots written by Snapshot.
+// It is increased whenever the format changes, Restore migrates the snapshots of older versions.
+const SnapshotVersion = 1
+
+// StateRestorer is implemented by Models which can restore there state from the data returned by there MarshalBinary.
+// It is the counterpart to encoding.BinaryUnmarshaler for Models which are no pointers,
+// since like Update it returns the changed Model.
+type StateRestorer interface {
+	RestoreState(data []byte) (tea.Model, error)
+}
+
+// snapshot is the saved form of a Boxer.
+type snapshot struct {
+	Version int    `json:"version"`
+	Layout  Layout `json:"layout"`
+	Focus   string `json:"focus,omitempty"`
+	// Models holds the state of the Models which implement encoding.BinaryMarshaler
+	Models map[string][]byte `json:"models,omitempty"`
+}
+
+// migrations upgrade a snapshot from the version of there index to the next version.
+var migrations = []func(data []byte) ([]byte, error){
+	// version 0 is a plain Layout, as returned by Boxer.Layout
+	func(data []byte) ([]byte, error) {
+		return json.Marshal(struct {
+			Version int             `json:"version"`
+			Layout  json.RawMessage `json:"layout"`
+		}{1, data})
+	},
+}
+
+// Snapshot saves the LayoutTree, the focus and the state of the Models which implement encoding.BinaryMarshaler,
+// so that they can be restored with Restore, for example after a restart.
+// The same limitations as for Layout apply.
+func (b *Boxer) Snapshot() ([]byte, error) {
+	s := snapshot{
+		Version: SnapshotVersion,
+		Layout:  b.Layout(),
+		Focus:   b.focus,
+	}
+	for _, address := range b.LayoutTree.leaves() {
+		marshaler, ok := b.ModelMap[address].(encoding.BinaryMarshaler)
+		if !ok {
+			continue
+		}
+		data, err := marshaler.MarshalBinary()
+		if err != nil {
+			return nil, &Error{Kind: KindInvalid, Address: address, Err: err}
+		}
+		if s.Models == nil {
+			s.Models = make(map[string][]byte)
+		}
+		s.Models[address] = data
+	}
+	return json.Marshal(s)
+}
+
+// Restore replaces the LayoutTree and the ModelMap with the ones saved by Snapshot.
+// The Models are made by the factories of the Registry (see Build) and then restore there saved state,
+// if they implement StateRestorer or encoding.BinaryUnmarshaler.
+// Snapshots of older versions are migrated, snapshots of newer versions are rejected.
+func (b *Boxer) Restore(data []byte, registry Registry) (tea.Cmd, error) {
+	var version struct {
+		Version int `json:"version"`
+	}
+	if err := json.Unmarshal(data, &version); err != nil {
+		return nil, &Error{Kind: KindInvalid, Err: err}
+	}
+	if version.Version < 0 {
+		return nil, newError(KindInvalid, "the snapshot has the invalid version %d", version.Version)
+	}
+	if version.Version > SnapshotVersion {
+		return nil, newError(KindInvalid, "the snapshot has the version %d, but only versions up to %d are supported", version.Version, SnapshotVersion)
+	}
+	if len(migrations) != SnapshotVersion {
+		return nil, newError(KindInvalid, "there are %d migrations for the snapshot version %d", len(migrations), SnapshotVersion)
+	}
+	for v := version.Version; v < SnapshotVersion; v++ {
+		var err error
+		if data, err = migrations[v](data); err != nil {
+			return nil, &Error{Kind: KindInvalid, Err: fmt.Errorf("migrating the snapshot from version %d: %w", v, err)}
+		}
+	}
+	var s snapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, &Error{Kind: KindInvalid, Err: err}
+	}
+
+	built := Boxer{}
+	root, err := built.build(s.Layout, registry)
+	if err != nil {
+		return nil, err
+	}
+	for address, state := range s.Models {
+		model, ok := built.ModelMap[address]
+		if !ok {
+			continue
+		}
+		switch m := model.(type) {
+		case StateRestorer:
+			model, err = m.RestoreState(state)
+		case encoding.BinaryUnmarshaler:
+			err = m.UnmarshalBinary(state)
+		}
+		if err != nil {
+			return nil, &Error{Kind: KindInvalid, Address: address, Err: err}
+		}
+		built.ModelMap[address] = model
+	}
+
+	b.focus = ""
+	cmd, err := b.adopt(root, built)
+	if err != nil || !b.LayoutTree.contains(s.Focus) {
+		return cmd, err
+	}
+	focusCmd, _ := b.Focus(s.Focus)
+	return tea.Batch(cmd, focusCmd), nil
+}
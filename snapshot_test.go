@@ -0,0 +1,91 @@
+package bubbleboxer
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// focusCounter counts the FocusMsg's it received and saves the count in snapshots.
+type focusCounter int
+
+func (c focusCounter) Init() tea.Cmd { return nil }
+func (c focusCounter) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if _, ok := msg.(FocusMsg); ok {
+		c++
+	}
+	return c, nil
+}
+func (c focusCounter) View() string                   { return strconv.Itoa(int(c)) }
+func (c focusCounter) MarshalBinary() ([]byte, error) { return []byte(c.View()), nil }
+func (c focusCounter) RestoreState(data []byte) (tea.Model, error) {
+	count, err := strconv.Atoi(string(data))
+	return focusCounter(count), err
+}
+
+func TestSnapshot(t *testing.T) {
+	registry := Registry{
+		"counter": func(string) (tea.Model, error) { return focusCounter(0), nil },
+		"text":    func(address string) (tea.Model, error) { return testModel(address), nil },
+	}
+	layout, err := ParseLayout("h(a:2, b)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	layout.Children[0].Type, layout.Children[1].Type = "counter", "text"
+
+	b := Boxer{}
+	if _, err := b.Build(layout, registry); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		b.FocusNext()
+	}
+	data, err := b.Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	restored := Boxer{}
+	if _, err := restored.Restore(data, registry); err != nil {
+		t.Fatal(err)
+	}
+	if restored.Focused() != "a" {
+		t.Errorf("expected the focus to be restored to 'a', but got '%s'", restored.Focused())
+	}
+	// two FocusMsg's before the snapshot and one after restoring the focus
+	if got := restored.ModelMap["a"]; got != focusCounter(3) {
+		t.Errorf("expected the state of the counter to be restored, but got: %v", got)
+	}
+	if _, err := restored.UpdateSize(tea.WindowSizeMsg{Width: 4, Height: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if got := restored.View(); got != "3 │b" {
+		t.Errorf("expected the restored view '3 │b', but got '%s'", got)
+	}
+
+	// a plain layout is a snapshot of version 0
+	old, err := json.Marshal(layout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := restored.Restore(old, registry); err != nil {
+		t.Fatal(err)
+	}
+	if restored.Focused() != "" || restored.ModelMap["a"] != focusCounter(0) {
+		t.Error("expected a plain layout to be restored without focus and state")
+	}
+
+	var e *Error
+	_, err = restored.Restore([]byte(`{"version": 99}`), registry)
+	if !errors.As(err, &e) || e.Kind != KindInvalid {
+		t.Errorf("expected a newer snapshot to be rejected, but got: %v", err)
+	}
+	_, err = restored.Restore([]byte(`{"version": -1}`), registry)
+	if !errors.As(err, &e) || e.Kind != KindInvalid {
+		t.Errorf("expected a negative version to be rejected, but got: %v", err)
+	}
+}
@@ -0,0 +1,587 @@
+package bubbleboxer
+
+import (
+	"errors"
+	"math"
+)
+
+// This file contains an incremental solver for linear equalities and inequalities with strengths,
+// following the Cassowary algorithm as it is implemented by the kiwi library.
+// It is used by the constraint layer (see Constraint) and kept private,
+// because only the layout-tree is meant to be constrained.
+
+var (
+	// errUnsatisfiable conveys that a required constraint conflicts with the other required constraints
+	errUnsatisfiable = errors.New("the required constraint conflicts with the other required constraints")
+	// errUnbounded conveys that the objective function of the solver is unbounded, which should not happen
+	errUnbounded = errors.New("the objective function is unbounded")
+	// errDuplicateEdit conveys that a variable was added twice as edit variable
+	errDuplicateEdit = errors.New("the variable is already an edit variable")
+	// errUnknownEdit conveys that a variable is not an edit variable
+	errUnknownEdit = errors.New("the variable is not an edit variable")
+	// errRequiredEdit conveys that an edit variable can not have the required strength
+	errRequiredEdit = errors.New("an edit variable can not have the required strength")
+	// errDualOptimize conveys that the dual optimization failed, which should not happen
+	errDualOptimize = errors.New("the dual optimization failed")
+)
+
+// strength values of the solver, they are combined so that no amount of weaker constraints outweighs a stronger one
+const (
+	strengthRequired = 1000*1000000 + 1000*1000 + 1000
+	strengthStrong   = 1000000
+	strengthMedium   = 1000
+	strengthWeak     = 1
+)
+
+func clipStrength(strength float64) float64 {
+	return math.Max(0, math.Min(strengthRequired, strength))
+}
+
+const epsilon = 1.0e-8
+
+func nearZero(value float64) bool {
+	return math.Abs(value) < epsilon
+}
+
+// variable is a value which is determined by the solver
+type variable struct {
+	name  string
+	value float64
+}
+
+// term is a variable multiplied with a coefficient
+type term struct {
+	variable    *variable
+	coefficient float64
+}
+
+// expression is the sum of the terms and the constant
+type expression struct {
+	terms    []term
+	constant float64
+}
+
+type relation int
+
+const (
+	relationEqual relation = iota
+	relationLessOrEqual
+	relationGreaterOrEqual
+)
+
+// constraint is the relation of the expression to zero: expression (==|<=|>=) 0
+type constraint struct {
+	expression expression
+	relation   relation
+	strength   float64
+}
+
+type symbolKind int
+
+const (
+	symbolInvalid symbolKind = iota
+	symbolExternal
+	symbolSlack
+	symbolError
+	symbolDummy
+)
+
+// symbol is a variable of the tableau, the id defines the order in which the symbols are considered
+type symbol struct {
+	id   int
+	kind symbolKind
+}
+
+func (s symbol) valid() bool { return s.kind != symbolInvalid }
+
+// row is the linear combination of symbols plus a constant
+type row struct {
+	constant float64
+	cells    map[symbol]float64
+}
+
+func newRow(constant float64) *row {
+	return &row{constant: constant, cells: make(map[symbol]float64)}
+}
+
+func (r *row) copy() *row {
+	c := newRow(r.constant)
+	for s, coefficient := range r.cells {
+		c.cells[s] = coefficient
+	}
+	return c
+}
+
+// add adds the value to the constant and returns the new constant
+func (r *row) add(value float64) float64 {
+	r.constant += value
+	return r.constant
+}
+
+func (r *row) insertSymbol(s symbol, coefficient float64) {
+	coefficient += r.cells[s]
+	if nearZero(coefficient) {
+		delete(r.cells, s)
+		return
+	}
+	r.cells[s] = coefficient
+}
+
+func (r *row) insertRow(other *row, coefficient float64) {
+	r.constant += other.constant * coefficient
+	for s, c := range other.cells {
+		r.insertSymbol(s, c*coefficient)
+	}
+}
+
+func (r *row) remove(s symbol) {
+	delete(r.cells, s)
+}
+
+func (r *row) reverseSign() {
+	r.constant = -r.constant
+	for s, c := range r.cells {
+		r.cells[s] = -c
+	}
+}
+
+// solveFor solves the row for the symbol, which has to be in the row
+func (r *row) solveFor(s symbol) {
+	coefficient := -1.0 / r.cells[s]
+	delete(r.cells, s)
+	r.constant *= coefficient
+	for k, c := range r.cells {
+		r.cells[k] = c * coefficient
+	}
+}
+
+// solveForPair solves the row for rhs where the row is equal to lhs
+func (r *row) solveForPair(lhs, rhs symbol) {
+	r.insertSymbol(lhs, -1.0)
+	r.solveFor(rhs)
+}
+
+func (r *row) coefficientFor(s symbol) float64 {
+	return r.cells[s]
+}
+
+// substitute replaces the symbol with the row
+func (r *row) substitute(s symbol, other *row) {
+	coefficient, ok := r.cells[s]
+	if !ok {
+		return
+	}
+	delete(r.cells, s)
+	r.insertRow(other, coefficient)
+}
+
+// sortedSymbols returns the symbols of the cells ordered by there id, so that the solver is deterministic
+func (r *row) sortedSymbols() []symbol {
+	symbols := make([]symbol, 0, len(r.cells))
+	for s := range r.cells {
+		symbols = append(symbols, s)
+	}
+	sortSymbols(symbols)
+	return symbols
+}
+
+func sortSymbols(symbols []symbol) {
+	// insertion sort, since the rows are small
+	for i := 1; i < len(symbols); i++ {
+		for j := i; j > 0 && symbols[j].id < symbols[j-1].id; j-- {
+			symbols[j], symbols[j-1] = symbols[j-1], symbols[j]
+		}
+	}
+}
+
+// tag holds the symbols which were added to the tableau for a constraint
+type tag struct {
+	marker symbol
+	other  symbol
+}
+
+type editInfo struct {
+	tag        tag
+	constraint *constraint
+	constant   float64
+}
+
+// solver holds the tableau of the simplex method
+type solver struct {
+	constraints map[*constraint]tag
+	rows        map[symbol]*row
+	variables   map[*variable]symbol
+	edits       map[*variable]*editInfo
+	infeasible  []symbol
+	objective   *row
+	artificial  *row
+	lastID      int
+}
+
+func newSolver() *solver {
+	return &solver{
+		constraints: make(map[*constraint]tag),
+		rows:        make(map[symbol]*row),
+		variables:   make(map[*variable]symbol),
+		edits:       make(map[*variable]*editInfo),
+		objective:   newRow(0),
+	}
+}
+
+func (s *solver) newSymbol(kind symbolKind) symbol {
+	s.lastID++
+	return symbol{id: s.lastID, kind: kind}
+}
+
+// sortedRows returns the basic symbols ordered by there id, so that the solver is deterministic
+func (s *solver) sortedRows() []symbol {
+	symbols := make([]symbol, 0, len(s.rows))
+	for sym := range s.rows {
+		symbols = append(symbols, sym)
+	}
+	sortSymbols(symbols)
+	return symbols
+}
+
+// addConstraint adds the constraint to the solver and optimizes the solution.
+func (s *solver) addConstraint(c *constraint) error {
+	t, r := s.createRow(c)
+	subject := s.chooseSubject(r, t)
+
+	if !subject.valid() && allDummies(r) {
+		if !nearZero(r.constant) {
+			s.removeObjectiveEffects(c, t)
+			return errUnsatisfiable
+		}
+		subject = t.marker
+	}
+
+	if !subject.valid() {
+		ok, err := s.addWithArtificialVariable(r)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			s.removeObjectiveEffects(c, t)
+			return errUnsatisfiable
+		}
+	} else {
+		r.solveFor(subject)
+		s.substitute(subject, r)
+		s.rows[subject] = r
+	}
+
+	s.constraints[c] = t
+	return s.optimize(s.objective)
+}
+
+// addEditVariable makes the variable suggestible with the given (not required) strength.
+func (s *solver) addEditVariable(v *variable, strength float64) error {
+	if _, ok := s.edits[v]; ok {
+		return errDuplicateEdit
+	}
+	strength = clipStrength(strength)
+	if strength >= strengthRequired {
+		return errRequiredEdit
+	}
+	c := &constraint{
+		expression: expression{terms: []term{{variable: v, coefficient: 1}}},
+		relation:   relationEqual,
+		strength:   strength,
+	}
+	if err := s.addConstraint(c); err != nil {
+		return err
+	}
+	s.edits[v] = &editInfo{tag: s.constraints[c], constraint: c}
+	return nil
+}
+
+// suggestValue suggests the value for the edit variable and reoptimizes the solution incrementally.
+func (s *solver) suggestValue(v *variable, value float64) error {
+	info, ok := s.edits[v]
+	if !ok {
+		return errUnknownEdit
+	}
+	delta := value - info.constant
+	info.constant = value
+
+	// check first if the positive error variable is basic
+	if r, ok := s.rows[info.tag.marker]; ok {
+		if r.add(-delta) < 0 {
+			s.infeasible = append(s.infeasible, info.tag.marker)
+		}
+		return s.dualOptimize()
+	}
+
+	// check next if the negative error variable is basic
+	if r, ok := s.rows[info.tag.other]; ok {
+		if r.add(delta) < 0 {
+			s.infeasible = append(s.infeasible, info.tag.other)
+		}
+		return s.dualOptimize()
+	}
+
+	// otherwise update each row where the error variables exist
+	for _, sym := range s.sortedRows() {
+		r := s.rows[sym]
+		coefficient := r.coefficientFor(info.tag.marker)
+		if coefficient != 0 && r.add(delta*coefficient) < 0 && sym.kind != symbolExternal {
+			s.infeasible = append(s.infeasible, sym)
+		}
+	}
+	return s.dualOptimize()
+}
+
+// updateVariables writes the current solution into the variables.
+func (s *solver) updateVariables() {
+	for v, sym := range s.variables {
+		if r, ok := s.rows[sym]; ok {
+			v.value = r.constant
+			continue
+		}
+		v.value = 0
+	}
+}
+
+func (s *solver) createRow(c *constraint) (tag, *row) {
+	r := newRow(c.expression.constant)
+	for _, t := range c.expression.terms {
+		if nearZero(t.coefficient) {
+			continue
+		}
+		sym := s.variableSymbol(t.variable)
+		if basic, ok := s.rows[sym]; ok {
+			r.insertRow(basic, t.coefficient)
+			continue
+		}
+		r.insertSymbol(sym, t.coefficient)
+	}
+
+	var t tag
+	switch c.relation {
+	case relationLessOrEqual, relationGreaterOrEqual:
+		coefficient := 1.0
+		if c.relation == relationGreaterOrEqual {
+			coefficient = -1.0
+		}
+		slack := s.newSymbol(symbolSlack)
+		t.marker = slack
+		r.insertSymbol(slack, coefficient)
+		if c.strength < strengthRequired {
+			errSym := s.newSymbol(symbolError)
+			t.other = errSym
+			r.insertSymbol(errSym, -coefficient)
+			s.objective.insertSymbol(errSym, c.strength)
+		}
+	case relationEqual:
+		if c.strength < strengthRequired {
+			plus := s.newSymbol(symbolError)
+			minus := s.newSymbol(symbolError)
+			t.marker = plus
+			t.other = minus
+			r.insertSymbol(plus, -1.0)
+			r.insertSymbol(minus, 1.0)
+			s.objective.insertSymbol(plus, c.strength)
+			s.objective.insertSymbol(minus, c.strength)
+		} else {
+			dummy := s.newSymbol(symbolDummy)
+			t.marker = dummy
+			r.insertSymbol(dummy, 1.0)
+		}
+	}
+
+	if r.constant < 0 {
+		r.reverseSign()
+	}
+	return t, r
+}
+
+func (s *solver) variableSymbol(v *variable) symbol {
+	if sym, ok := s.variables[v]; ok {
+		return sym
+	}
+	sym := s.newSymbol(symbolExternal)
+	s.variables[v] = sym
+	return sym
+}
+
+func (s *solver) chooseSubject(r *row, t tag) symbol {
+	for _, sym := range r.sortedSymbols() {
+		if sym.kind == symbolExternal {
+			return sym
+		}
+	}
+	if t.marker.kind == symbolSlack || t.marker.kind == symbolError {
+		if r.coefficientFor(t.marker) < 0 {
+			return t.marker
+		}
+	}
+	if t.other.kind == symbolSlack || t.other.kind == symbolError {
+		if r.coefficientFor(t.other) < 0 {
+			return t.other
+		}
+	}
+	return symbol{}
+}
+
+func allDummies(r *row) bool {
+	for sym := range r.cells {
+		if sym.kind != symbolDummy {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *solver) addWithArtificialVariable(r *row) (bool, error) {
+	art := s.newSymbol(symbolSlack)
+	s.rows[art] = r.copy()
+	s.artificial = r.copy()
+
+	if err := s.optimize(s.artificial); err != nil {
+		return false, err
+	}
+	success := nearZero(s.artificial.constant)
+	s.artificial = nil
+
+	if basic, ok := s.rows[art]; ok {
+		delete(s.rows, art)
+		if len(basic.cells) == 0 {
+			return success, nil
+		}
+		entering := anyPivotableSymbol(basic)
+		if !entering.valid() {
+			return false, nil
+		}
+		basic.solveForPair(art, entering)
+		s.substitute(entering, basic)
+		s.rows[entering] = basic
+	}
+
+	for _, basic := range s.rows {
+		basic.remove(art)
+	}
+	s.objective.remove(art)
+	return success, nil
+}
+
+func (s *solver) substitute(sym symbol, r *row) {
+	for _, basicSym := range s.sortedRows() {
+		basic := s.rows[basicSym]
+		basic.substitute(sym, r)
+		if basicSym.kind != symbolExternal && basic.constant < 0 {
+			s.infeasible = append(s.infeasible, basicSym)
+		}
+	}
+	s.objective.substitute(sym, r)
+	if s.artificial != nil {
+		s.artificial.substitute(sym, r)
+	}
+}
+
+func (s *solver) optimize(objective *row) error {
+	for {
+		entering := getEnteringSymbol(objective)
+		if !entering.valid() {
+			return nil
+		}
+		leaving, ok := s.getLeavingRow(entering)
+		if !ok {
+			return errUnbounded
+		}
+		r := s.rows[leaving]
+		delete(s.rows, leaving)
+		r.solveForPair(leaving, entering)
+		s.substitute(entering, r)
+		s.rows[entering] = r
+	}
+}
+
+func (s *solver) dualOptimize() error {
+	for len(s.infeasible) > 0 {
+		leaving := s.infeasible[len(s.infeasible)-1]
+		s.infeasible = s.infeasible[:len(s.infeasible)-1]
+
+		r, ok := s.rows[leaving]
+		if !ok || nearZero(r.constant) || r.constant >= 0 {
+			continue
+		}
+		entering := s.getDualEnteringSymbol(r)
+		if !entering.valid() {
+			return errDualOptimize
+		}
+		delete(s.rows, leaving)
+		r.solveForPair(leaving, entering)
+		s.substitute(entering, r)
+		s.rows[entering] = r
+	}
+	return nil
+}
+
+func getEnteringSymbol(objective *row) symbol {
+	for _, sym := range objective.sortedSymbols() {
+		if sym.kind != symbolDummy && objective.cells[sym] < 0 {
+			return sym
+		}
+	}
+	return symbol{}
+}
+
+func (s *solver) getDualEnteringSymbol(r *row) symbol {
+	var entering symbol
+	ratio := math.MaxFloat64
+	for _, sym := range r.sortedSymbols() {
+		coefficient := r.cells[sym]
+		if coefficient > 0 && sym.kind != symbolDummy {
+			if current := s.objective.coefficientFor(sym) / coefficient; current < ratio {
+				ratio = current
+				entering = sym
+			}
+		}
+	}
+	return entering
+}
+
+func anyPivotableSymbol(r *row) symbol {
+	for _, sym := range r.sortedSymbols() {
+		if sym.kind == symbolSlack || sym.kind == symbolError {
+			return sym
+		}
+	}
+	return symbol{}
+}
+
+func (s *solver) getLeavingRow(entering symbol) (symbol, bool) {
+	ratio := math.MaxFloat64
+	var found symbol
+	for _, sym := range s.sortedRows() {
+		if sym.kind == symbolExternal {
+			continue
+		}
+		coefficient := s.rows[sym].coefficientFor(entering)
+		if coefficient < 0 {
+			if current := -s.rows[sym].constant / coefficient; current < ratio {
+				ratio = current
+				found = sym
+			}
+		}
+	}
+	return found, found.valid()
+}
+
+func (s *solver) removeObjectiveEffects(c *constraint, t tag) {
+	if t.marker.kind == symbolError {
+		s.removeMarkerEffects(t.marker, c.strength)
+	}
+	if t.other.kind == symbolError {
+		s.removeMarkerEffects(t.other, c.strength)
+	}
+}
+
+func (s *solver) removeMarkerEffects(marker symbol, strength float64) {
+	if r, ok := s.rows[marker]; ok {
+		s.objective.insertRow(r, -strength)
+		return
+	}
+	s.objective.insertSymbol(marker, -strength)
+}
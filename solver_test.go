@@ -0,0 +1,61 @@
+package bubbleboxer
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSolver(t *testing.T) {
+	s := newSolver()
+	x := &variable{name: "x"}
+	y := &variable{name: "y"}
+
+	// x + y == 10
+	sum := &constraint{
+		expression: expression{terms: []term{{x, 1}, {y, 1}}, constant: -10},
+		relation:   relationEqual,
+		strength:   strengthRequired,
+	}
+	if err := s.addConstraint(sum); err != nil {
+		t.Fatal(err)
+	}
+	// x >= 2
+	if err := s.addConstraint(&constraint{
+		expression: expression{terms: []term{{x, 1}}, constant: -2},
+		relation:   relationGreaterOrEqual,
+		strength:   strengthRequired,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.addEditVariable(x, strengthStrong); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range []struct {
+		suggest float64
+		x, y    float64
+	}{
+		{3, 3, 7},
+		{8, 8, 2},
+		// the required constraint wins over the suggestion
+		{1, 2, 8},
+	} {
+		if err := s.suggestValue(x, c.suggest); err != nil {
+			t.Fatal(err)
+		}
+		s.updateVariables()
+		if !nearZero(x.value-c.x) || !nearZero(y.value-c.y) {
+			t.Errorf("suggesting %g: expected x=%g and y=%g but got x=%g and y=%g", c.suggest, c.x, c.y, x.value, y.value)
+		}
+	}
+
+	// x <= 1 conflicts with x >= 2
+	err := s.addConstraint(&constraint{
+		expression: expression{terms: []term{{x, 1}}, constant: -1},
+		relation:   relationLessOrEqual,
+		strength:   strengthRequired,
+	})
+	if !errors.Is(err, errUnsatisfiable) {
+		t.Errorf("expected the conflicting constraint to be unsatisfiable but got: %v", err)
+	}
+}
@@ -0,0 +1,37 @@
+package bubbleboxer
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// resetSequences are the escape sequences which reset all text attributes including the background
+var resetSequences = []string{"\x1b[0m", "\x1b[m"}
+
+// backgroundSequence returns the escape sequence which sets the background to the color
+// or an empty string if the color profile of the terminal has no colors.
+func backgroundSequence(color lipgloss.TerminalColor) string {
+	styled := lipgloss.NewStyle().Background(color).Render(SPACE)
+	if i := strings.Index(styled, SPACE); i > 0 {
+		return styled[:i]
+	}
+	return ""
+}
+
+// fill pads the lines to the width and height and paints the background of the whole area with the color.
+// The background is set again after every reset within the lines, so that it is not lost after styled text.
+func fill(m measure, lines []string, width, height int, color lipgloss.TerminalColor) []string {
+	lines = m.pad(lines, width, height)
+	background := backgroundSequence(color)
+	if background == "" {
+		return lines
+	}
+	for i, line := range lines {
+		for _, reset := range resetSequences {
+			line = strings.ReplaceAll(line, reset, reset+background)
+		}
+		lines[i] = background + line + resetSequences[0]
+	}
+	return lines
+}
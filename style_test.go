@@ -0,0 +1,65 @@
+package bubbleboxer
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+func TestStyles(t *testing.T) {
+	defer lipgloss.SetColorProfile(lipgloss.ColorProfile())
+	lipgloss.SetColorProfile(termenv.ANSI)
+
+	separatorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+	focusStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("2"))
+	b := Boxer{SeparatorStyle: &separatorStyle, FocusStyle: &focusStyle}
+	b.LayoutTree = Node{
+		Children: []Node{
+			stripErr(b.CreateLeaf("a", testModel("a"))),
+			stripErr(b.CreateLeaf("b", testModel("b"))),
+			stripErr(b.CreateLeaf("c", testModel("c"))),
+		},
+	}
+	if _, err := b.UpdateSize(tea.WindowSizeMsg{Width: 5, Height: 1}); err != nil {
+		t.Fatal(err)
+	}
+	want := "a" + separatorStyle.Render("│") + "b" + separatorStyle.Render("│") + "c"
+	if got := b.View(); got != want {
+		t.Errorf("expected:\n%q\nbut got:\n%q", want, got)
+	}
+
+	if _, err := b.Focus("a"); err != nil {
+		t.Fatal(err)
+	}
+	want = "a" + focusStyle.Render("│") + "b" + separatorStyle.Render("│") + "c"
+	if got := b.View(); got != want {
+		t.Errorf("expected the separator next to the focused leaf to be highlighted:\n%q\nbut got:\n%q", want, got)
+	}
+}
+
+func TestBackground(t *testing.T) {
+	defer lipgloss.SetColorProfile(lipgloss.ColorProfile())
+	lipgloss.SetColorProfile(termenv.ANSI)
+
+	b := Boxer{}
+	leaf := stripErr(b.CreateLeaf("a", testModel("x\x1b[0my")))
+	leaf.Background = lipgloss.Color("4")
+	b.LayoutTree = leaf
+	if _, err := b.UpdateSize(tea.WindowSizeMsg{Width: 3, Height: 2}); err != nil {
+		t.Fatal(err)
+	}
+	background := backgroundSequence(leaf.Background)
+	if background == "" {
+		t.Fatal("expected a background sequence with a color profile")
+	}
+	want := strings.Join([]string{
+		background + "x\x1b[0m" + background + "y " + "\x1b[0m",
+		background + "   " + "\x1b[0m",
+	}, NEWLINE)
+	if got := b.View(); got != want {
+		t.Errorf("expected the whole leaf to be filled:\n%q\nbut got:\n%q", want, got)
+	}
+}
@@ -0,0 +1,381 @@
+package bubbleboxer
+
+import (
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+	"github.com/rivo/uniseg"
+)
+
+const (
+	esc = '\x1b'
+	bel = '\x07'
+
+	// sgrReset resets all text attributes
+	sgrReset = "\x1b[0m"
+	// hyperlinkClose ends a OSC 8 hyperlink
+	hyperlinkClose = "\x1b]8;;\x1b\\"
+)
+
+// ansiState is the state of the terminal which is left open by the escape sequences of a line.
+type ansiState struct {
+	// sgr holds the SGR sequences (colors, bold, ...) which are active since the last reset
+	sgr []string
+	// hyperlink is the OSC 8 sequence of the hyperlink which is open or empty if there is none
+	hyperlink string
+}
+
+// open returns the sequences which restore the state.
+func (s ansiState) open() string {
+	return strings.Join(s.sgr, "") + s.hyperlink
+}
+
+// close returns the sequences which end the state, so that it does not leak into the following text.
+func (s ansiState) close() string {
+	var c string
+	if s.hyperlink != "" {
+		c += hyperlinkClose
+	}
+	if len(s.sgr) > 0 {
+		c += sgrReset
+	}
+	return c
+}
+
+// update changes the state according to the escape sequence.
+func (s *ansiState) update(sequence string) {
+	switch {
+	case strings.HasPrefix(sequence, "\x1b[") && strings.HasSuffix(sequence, "m"):
+		params := sequence[2 : len(sequence)-1]
+		if params == "" || params == "0" {
+			s.sgr = nil
+			return
+		}
+		if strings.HasPrefix(params, "0;") {
+			s.sgr = nil
+		}
+		s.sgr = append(s.sgr, sequence)
+	case strings.HasPrefix(sequence, "\x1b]8;"):
+		// the parameters and the URI are separated by a ';', a empty URI closes the link
+		body := strings.TrimPrefix(sequence, "\x1b]8;")
+		body = strings.TrimSuffix(strings.TrimSuffix(body, "\x1b\\"), string(bel))
+		if i := strings.IndexByte(body, ';'); i >= 0 && body[i+1:] != "" {
+			s.hyperlink = sequence
+			return
+		}
+		s.hyperlink = ""
+	}
+}
+
+// sequenceEnd returns the index after the escape sequence which starts at the index i of the line.
+func sequenceEnd(line string, i int) int {
+	if i+1 >= len(line) {
+		return len(line)
+	}
+	switch line[i+1] {
+	case '[':
+		// CSI: parameters till a final byte
+		for j := i + 2; j < len(line); j++ {
+			if line[j] >= 0x40 && line[j] <= 0x7e {
+				return j + 1
+			}
+		}
+	case ']':
+		// OSC: terminated by BEL or ST
+		for j := i + 2; j < len(line); j++ {
+			if line[j] == bel {
+				return j + 1
+			}
+			if line[j] == esc && j+1 < len(line) && line[j+1] == '\\' {
+				return j + 2
+			}
+		}
+	default:
+		return i + 2
+	}
+	return len(line)
+}
+
+// isolate closes the styles and hyperlinks which are left open at the end of each line
+// and opens them again at the start of the next line,
+// so that they do not leak into the separators or the neighbouring panes.
+func isolate(lines []string) []string {
+	var state ansiState
+	for l, line := range lines {
+		before := state.open()
+		for i := 0; i < len(line); i++ {
+			if line[i] != esc {
+				continue
+			}
+			end := sequenceEnd(line, i)
+			state.update(line[i:end])
+			i = end - 1
+		}
+		lines[l] = before + line + state.close()
+	}
+	return lines
+}
+
+// WidthFunc returns how many columns the text occupies in the terminal.
+// The text contains no escape sequences and is measured by grapheme clusters while clipping.
+type WidthFunc func(text string) int
+
+// GraphemeWidth measures the text by its grapheme clusters, so that emoji sequences joined with zero width joiners,
+// flags and combining marks are counted as the single character the terminal shows.
+func GraphemeWidth(text string) int {
+	if isASCII(text) {
+		return len(text)
+	}
+	var width int
+	g := uniseg.NewGraphemes(text)
+	for g.Next() {
+		width += clusterWidth(g.Runes())
+	}
+	return width
+}
+
+// clusterWidth returns the width of a single grapheme cluster.
+func clusterWidth(cluster []rune) int {
+	var width int
+	for _, r := range cluster {
+		switch {
+		case r == '\u200d':
+			// zero width joiner
+		case r == '\ufe0f':
+			// variation selector which requests the emoji presentation
+			return 2
+		case r >= '\U0001f1e6' && r <= '\U0001f1ff':
+			// regional indicators, which form a flag in pairs
+			return 2
+		default:
+			if width == 0 {
+				width = runewidth.RuneWidth(r)
+			}
+		}
+	}
+	return width
+}
+
+// isASCII returns if the text consists only of printable ASCII characters,
+// which are one column wide each and need no further measuring.
+func isASCII(text string) bool {
+	for i := 0; i < len(text); i++ {
+		if text[i] < 0x20 || text[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// TabWidth is the default distance between tab stops, if a Boxer has no TabWidth.
+const TabWidth = 8
+
+// measure measures and cuts lines with escape sequences by the width of there grapheme clusters.
+// Without a WidthFunc GraphemeWidth is used.
+type measure struct {
+	width    WidthFunc
+	tabWidth int
+}
+
+// defaultMeasure is used if there is no Boxer to take the configuration from
+var defaultMeasure = measure{tabWidth: TabWidth}
+
+// token is a grapheme cluster or an escape sequence of a line
+type token struct {
+	text   string
+	escape bool
+}
+
+// tokens splits the line into escape sequences and grapheme clusters.
+func tokens(line string) []token {
+	var result []token
+	plain := func(text string) {
+		g := uniseg.NewGraphemes(text)
+		for g.Next() {
+			result = append(result, token{text: g.Str()})
+		}
+	}
+	start := 0
+	for i := 0; i < len(line); i++ {
+		if line[i] != esc {
+			continue
+		}
+		plain(line[start:i])
+		end := sequenceEnd(line, i)
+		result = append(result, token{text: line[i:end], escape: true})
+		start = end
+		i = end - 1
+	}
+	plain(line[start:])
+	return result
+}
+
+// of returns the width of the token.
+func (m measure) of(t token) int {
+	if t.escape {
+		return 0
+	}
+	return m.text(t.text)
+}
+
+// text returns the width of the text without escape sequences.
+func (m measure) text(text string) int {
+	if m.width == nil {
+		return GraphemeWidth(text)
+	}
+	return m.width(text)
+}
+
+// lineWidth returns the amount of columns the line occupies, the escape sequences are ignored.
+// The text between the escape sequences is measured as a whole.
+func (m measure) lineWidth(line string) int {
+	if m.width == nil && isASCII(line) {
+		return len(line)
+	}
+	var width, start int
+	for i := 0; i < len(line); i++ {
+		if line[i] != esc {
+			continue
+		}
+		if i > start {
+			width += m.text(line[start:i])
+		}
+		start = sequenceEnd(line, i)
+		i = start - 1
+	}
+	if start < len(line) {
+		width += m.text(line[start:])
+	}
+	return width
+}
+
+// expandTabs replaces the tabs with spaces till the next tab stop.
+func (m measure) expandTabs(line string) string {
+	if !strings.Contains(line, "\t") {
+		return line
+	}
+	tabWidth := m.tabWidth
+	if tabWidth <= 0 {
+		tabWidth = TabWidth
+	}
+	var b strings.Builder
+	var column int
+	for _, t := range tokens(line) {
+		if t.text == "\t" {
+			spaces := tabWidth - column%tabWidth
+			b.WriteString(strings.Repeat(SPACE, spaces))
+			column += spaces
+			continue
+		}
+		b.WriteString(t.text)
+		column += m.of(t)
+	}
+	return b.String()
+}
+
+// clip cuts off the line so that it is at most width columns wide including the tail, which marks the cut.
+// A wide character which would straddle the edge is replaced by spaces.
+// The escape sequences after the cut are kept, so that the styles are still reset.
+func (m measure) clip(line string, width int, tail string) string {
+	if m.lineWidth(line) <= width {
+		return line
+	}
+	budget := width - m.lineWidth(tail)
+	if budget < 0 {
+		tail, budget = "", width
+	}
+	if m.width == nil && isASCII(line) {
+		return line[:budget] + tail
+	}
+	var kept, rest strings.Builder
+	var used int
+	cut := false
+	for _, t := range tokens(line) {
+		if t.escape {
+			if cut {
+				rest.WriteString(t.text)
+			} else {
+				kept.WriteString(t.text)
+			}
+			continue
+		}
+		if cut {
+			continue
+		}
+		w := m.of(t)
+		if used+w > budget {
+			kept.WriteString(strings.Repeat(SPACE, budget-used))
+			kept.WriteString(tail)
+			cut = true
+			continue
+		}
+		kept.WriteString(t.text)
+		used += w
+	}
+	return kept.String() + rest.String()
+}
+
+// wrap breaks the line at the spaces so that every resulting line is at most width columns wide.
+// Words which are wider than width are broken within.
+func (m measure) wrap(line string, width int) []string {
+	if m.lineWidth(line) <= width {
+		return []string{line}
+	}
+	var lines []string
+	var current strings.Builder
+	var currentWidth int
+	newLine := func() {
+		lines = append(lines, current.String())
+		current.Reset()
+		currentWidth = 0
+	}
+
+	var word []token
+	var wordWidth int
+	placeWord := func() {
+		if currentWidth > 0 && currentWidth+1+wordWidth > width {
+			newLine()
+		} else if currentWidth > 0 {
+			current.WriteString(SPACE)
+			currentWidth++
+		}
+		for _, t := range word {
+			w := m.of(t)
+			if currentWidth+w > width && currentWidth > 0 {
+				newLine()
+			}
+			current.WriteString(t.text)
+			currentWidth += w
+		}
+		word, wordWidth = nil, 0
+	}
+
+	for _, t := range tokens(line) {
+		if t.text == SPACE {
+			placeWord()
+			continue
+		}
+		word = append(word, t)
+		wordWidth += m.of(t)
+	}
+	placeWord()
+	return append(lines, current.String())
+}
+
+// pad fills up the lines with spaces to the width and adds empty lines till the height is reached.
+func (m measure) pad(lines []string, width, height int) []string {
+	for len(lines) < height {
+		lines = append(lines, "")
+	}
+	for i, line := range lines {
+		if lineWidth := m.lineWidth(line); lineWidth < width {
+			lines[i] = line + strings.Repeat(SPACE, width-lineWidth)
+		}
+	}
+	return lines
+}
+
+// measure returns the measure configured by WidthFunc and TabWidth.
+func (b *Boxer) measure() measure {
+	return measure{width: b.WidthFunc, tabWidth: b.TabWidth}
+}
@@ -0,0 +1,99 @@
+package bubbleboxer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestIsolate(t *testing.T) {
+	link := "\x1b]8;;https://example.com\x1b\\"
+	got := isolate([]string{
+		"\x1b[31mred",
+		"still red\x1b[0m plain",
+		link + "link",
+		"text" + hyperlinkClose,
+		"plain",
+	})
+	want := []string{
+		"\x1b[31mred" + sgrReset,
+		"\x1b[31m" + "still red\x1b[0m plain",
+		link + "link" + hyperlinkClose,
+		link + "text" + hyperlinkClose,
+		"plain",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected:\n%q\nbut got:\n%q", want, got)
+	}
+
+	b := Boxer{}
+	b.LayoutTree = Node{
+		Children: []Node{
+			stripErr(b.CreateLeaf("red", testModel("\x1b[31ma\nb"))),
+			stripErr(b.CreateLeaf("plain", testModel("c\nd"))),
+		},
+	}
+	if _, err := b.UpdateSize(tea.WindowSizeMsg{Width: 3, Height: 2}); err != nil {
+		t.Fatal(err)
+	}
+	wantView := strings.Join([]string{
+		"\x1b[31ma" + sgrReset + "│c",
+		"\x1b[31mb" + sgrReset + "│d",
+	}, NEWLINE)
+	if got := b.View(); got != wantView {
+		t.Errorf("expected the color to end before the separator:\n%q\nbut got:\n%q", wantView, got)
+	}
+}
+
+func TestMeasure(t *testing.T) {
+	for text, want := range map[string]int{
+		"abc":                  3,
+		"界":                    2,
+		"e\u0301":              1,
+		"\U0001F1E9\U0001F1EA": 2,
+		"\U0001F468\u200d\U0001F469\u200d\U0001F467": 2,
+		"\u2764\ufe0f": 2,
+	} {
+		if got := GraphemeWidth(text); got != want {
+			t.Errorf("expected '%s' to be %d wide but got %d", text, want, got)
+		}
+	}
+
+	m := measure{width: GraphemeWidth, tabWidth: 4}
+	if got := m.expandTabs("a\tb"); got != "a   b" {
+		t.Errorf("expected the tab to be expanded to the next tab stop but got %q", got)
+	}
+	if got := m.clip("a界b", 2, ""); got != "a " {
+		t.Errorf("expected the wide character on the edge to be replaced by a space but got %q", got)
+	}
+	if got := m.clip("\x1b[31mabc\x1b[0m", 2, "…"); got != "\x1b[31ma…\x1b[0m" {
+		t.Errorf("expected the line to be cut with the tail and the reset to be kept but got %q", got)
+	}
+	if got := m.wrap("ab cd efghij", 5); !reflect.DeepEqual(got, []string{"ab cd", "efghi", "j"}) {
+		t.Errorf("expected the line to be wrapped at the space and within the long word but got %q", got)
+	}
+
+	// the default measure takes shortcuts for plain ASCII text
+	if got := defaultMeasure.lineWidth("\x1b[31mab界\x1b[0m c"); got != 6 {
+		t.Errorf("expected the line to be 6 wide but got %d", got)
+	}
+	if got := defaultMeasure.clip("abcdef", 3, "…"); got != "ab…" {
+		t.Errorf("expected the plain line to be cut with the tail but got %q", got)
+	}
+
+	b := Boxer{WidthFunc: func(text string) int { return 2 * len(text) }}
+	b.LayoutTree = Node{
+		Children: []Node{
+			stripErr(b.CreateLeaf("a", testModel("ab"))),
+			stripErr(b.CreateLeaf("b", testModel("c"))),
+		},
+	}
+	if _, err := b.UpdateSize(tea.WindowSizeMsg{Width: 9, Height: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if got := b.View(); got != "ab│c  " {
+		t.Errorf("expected the custom WidthFunc to be used for the padding but got %q", got)
+	}
+}
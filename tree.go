@@ -0,0 +1,240 @@
+package bubbleboxer
+
+import (
+	"fmt"
+	"math"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Find returns the leaf with the address, the pointer is only valid till the LayoutTree is changed.
+func (b *Boxer) Find(address string) (*Node, error) {
+	path, err := b.leafPath(address)
+	if err != nil {
+		return nil, err
+	}
+	return b.LayoutTree.at(path), nil
+}
+
+// InsertBefore adds the node (with leaves from CreateLeaf) as sibling in front of the leaf with the address.
+func (b *Boxer) InsertBefore(address string, node Node) (tea.Cmd, error) {
+	return b.insert(address, node, 0)
+}
+
+// InsertAfter adds the node (with leaves from CreateLeaf) as sibling behind the leaf with the address.
+func (b *Boxer) InsertAfter(address string, node Node) (tea.Cmd, error) {
+	return b.insert(address, node, 1)
+}
+
+func (b *Boxer) insert(address string, node Node, offset int) (tea.Cmd, error) {
+	path, err := b.leafPath(address)
+	if err != nil {
+		return nil, err
+	}
+	if len(path) == 0 {
+		return nil, &Error{Kind: KindInvalid, Address: address, Err: fmt.Errorf("the root has no parent to insert a sibling into")}
+	}
+	if err := b.checkLeaves(node, ""); err != nil {
+		return nil, err
+	}
+	parent := b.LayoutTree.at(path[:len(path)-1])
+	if parent.Grid != nil {
+		return nil, &Error{Kind: KindInvalid, Path: path, Address: address, Err: fmt.Errorf("a sibling can not be inserted into a grid, use Place instead")}
+	}
+	parent.splitSize(path[len(path)-1], offset, 0.5)
+	i := path[len(path)-1] + offset
+	parent.Children = append(parent.Children[:i], append([]Node{node}, parent.Children[i:]...)...)
+	return b.relayout()
+}
+
+// Remove removes the leaf with the address, its Model and the nodes which are left without children (see Collapse).
+func (b *Boxer) Remove(address string) (tea.Cmd, error) {
+	path, err := b.leafPath(address)
+	if err != nil {
+		return nil, err
+	}
+	removed := *b.LayoutTree.at(path)
+	if len(path) == 0 {
+		b.LayoutTree = Node{}
+		b.forget(removed)
+		return nil, nil
+	}
+	depth := b.LayoutTree.removeAt(path)
+	if b.Collapse {
+		// only the ancestors of the removed leaf can be left with a single child
+		for ; depth >= 0; depth-- {
+			b.LayoutTree.at(path[:depth]).collapse()
+		}
+	}
+	b.forget(removed)
+	return b.relayout()
+}
+
+// Replace puts the node at the place of the leaf with the address and removes the Model of the leaf, unless the node contains it.
+func (b *Boxer) Replace(address string, node Node) (tea.Cmd, error) {
+	path, err := b.leafPath(address)
+	if err != nil {
+		return nil, err
+	}
+	if err := b.checkLeaves(node, address); err != nil {
+		return nil, err
+	}
+	leaf := b.LayoutTree.at(path)
+	replaced := *leaf
+	*leaf = node
+	b.forget(replaced)
+	return b.relayout()
+}
+
+// Swap exchanges the places of the leaves with the addresses, the Sizing's stay at there places.
+func (b *Boxer) Swap(first, second string) (tea.Cmd, error) {
+	firstPath, err := b.leafPath(first)
+	if err != nil {
+		return nil, err
+	}
+	secondPath, err := b.leafPath(second)
+	if err != nil {
+		return nil, err
+	}
+	a, c := b.LayoutTree.at(firstPath), b.LayoutTree.at(secondPath)
+	a.Sizing, c.Sizing = c.Sizing, a.Sizing
+	*a, *c = *c, *a
+	return b.relayout()
+}
+
+// leafPath returns the path to the leaf with the address or a error of the KindNotFound.
+func (b *Boxer) leafPath(address string) ([]int, error) {
+	path := b.LayoutTree.pathTo(address)
+	if path == nil {
+		return nil, &Error{Kind: KindNotFound, Address: address, Err: fmt.Errorf("no leaf with address '%s' found in the layout-tree", address)}
+	}
+	return path, nil
+}
+
+// relayout updates the size with the last known size, if there is one.
+func (b *Boxer) relayout() (tea.Cmd, error) {
+	if b.lastSize == (tea.WindowSizeMsg{}) {
+		return nil, nil
+	}
+	return b.UpdateSize(b.lastSize)
+}
+
+// checkLeaves returns a error of the KindInvalid if a leaf of the node, besides the one with the address except,
+// is already part of the LayoutTree, since each address has to be unique.
+func (b *Boxer) checkLeaves(node Node, except string) error {
+	for _, address := range node.leaves() {
+		if address != except && b.LayoutTree.contains(address) {
+			return &Error{Kind: KindInvalid, Address: address, Err: fmt.Errorf("the address '%s' is already used in the Layout-tree", address)}
+		}
+	}
+	return nil
+}
+
+// forget removes the Models of the leaves of the node, which are no longer part of the LayoutTree.
+func (b *Boxer) forget(node Node) {
+	for _, address := range node.leaves() {
+		if b.LayoutTree.contains(address) {
+			continue
+		}
+		delete(b.ModelMap, address)
+		delete(b.initialized, address)
+		if b.focus == address {
+			b.focus = ""
+		}
+	}
+}
+
+// removeAt removes the descendant at the path and all ancestors which are left without children.
+// It returns the depth of the ancestor which is left with one child less.
+func (n *Node) removeAt(path []int) int {
+	if len(path) == 0 {
+		return 0
+	}
+	i := path[0]
+	if len(path) > 1 {
+		depth := n.Children[i].removeAt(path[1:])
+		if len(n.Children[i].Children) > 0 {
+			return depth + 1
+		}
+	}
+	n.removeSize(i)
+	n.Children = append(n.Children[:i], n.Children[i+1:]...)
+	if n.Grid != nil {
+		n.Grid.Cells = append(n.Grid.Cells[:i], n.Grid.Cells[i+1:]...)
+	}
+	return 0
+}
+
+// collapse replaces the node with its child, if it has only a single one.
+// The child takes over the Sizing of the node, since it takes over its place.
+// The children of a grid are not collapsed into it, since they need there cell,
+// and neither are the children of nodes with a Frame, Border or SeparatorStyle, since those would be lost.
+func (n *Node) collapse() {
+	if len(n.Children) != 1 || n.Grid != nil || n.Frame != nil || n.Border != nil || n.SeparatorStyle != nil {
+		return
+	}
+	sizing := n.Sizing
+	*n = n.Children[0]
+	n.Sizing = sizing
+}
+
+// Split places a new leaf for the model behind (or below if vertical) the leaf with the address, which keeps the ratio of its space.
+// The new leaf becomes a sibling if the parent has the same orientation, otherwise both are wrapped in a new node.
+func (b *Boxer) Split(address, newAddress string, model tea.Model, vertical bool, ratio float64) (tea.Cmd, error) {
+	if ratio <= 0 || ratio >= 1 {
+		return nil, &Error{Kind: KindInvalid, Address: address, Err: fmt.Errorf("the ratio has to be between zero and one but is %g", ratio)}
+	}
+	path, err := b.leafPath(address)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := b.ModelMap[newAddress]; ok {
+		return nil, &Error{Kind: KindInvalid, Address: newAddress, Err: fmt.Errorf("there is already a Model with the address '%s'", newAddress)}
+	}
+	newLeaf, err := b.CreateLeaf(newAddress, model)
+	if err != nil {
+		return nil, err
+	}
+	leaf := b.LayoutTree.at(path)
+
+	if len(path) > 0 {
+		parent := b.LayoutTree.at(path[:len(path)-1])
+		if parent.Grid == nil && parent.SizeFunc == nil && parent.VerticalStacked == vertical {
+			// share the space of the leaf within the parent
+			leaf.Sizing, newLeaf.Sizing = leaf.Sizing.split(ratio, parent.borderWidth())
+			parent.splitSize(path[len(path)-1], 1, ratio)
+			i := path[len(path)-1] + 1
+			parent.Children = append(parent.Children[:i], append([]Node{newLeaf}, parent.Children[i:]...)...)
+			return b.relayout()
+		}
+	}
+
+	node := Node{VerticalStacked: vertical, Sizing: leaf.Sizing}
+	old := *leaf
+	old.Sizing, newLeaf.Sizing = Weight(ratio), Weight(1-ratio)
+	node.Children = []Node{old, newLeaf}
+	*leaf = node
+	return b.relayout()
+}
+
+// split divides the Sizing into two, the first one gets the ratio of it, the second one the rest without the border.
+// Min and Max are kept by the first one.
+func (s Sizing) split(ratio float64, border int) (Sizing, Sizing) {
+	first, second := Sizing{Min: s.Min, Max: s.Max}, Sizing{}
+	switch {
+	case s.Fixed > 0:
+		first.Fixed = int(math.Round(float64(s.Fixed-border) * ratio))
+		if first.Fixed < 1 {
+			first.Fixed = 1
+		}
+		second.Fixed = s.Fixed - border - first.Fixed
+		if second.Fixed < 1 {
+			second.Fixed = 1
+		}
+	case s.Percent > 0:
+		first.Percent, second.Percent = s.Percent*ratio, s.Percent*(1-ratio)
+	default:
+		first.Weight, second.Weight = s.weight()*ratio, s.weight()*(1-ratio)
+	}
+	return first, second
+}
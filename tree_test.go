@@ -0,0 +1,167 @@
+package bubbleboxer
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestTreeMutation(t *testing.T) {
+	b := Boxer{Collapse: true}
+	b.LayoutTree = Node{
+		Children: []Node{
+			stripErr(b.CreateLeaf("a", testModel("a"))),
+			{
+				VerticalStacked: true,
+				Children: []Node{
+					stripErr(b.CreateLeaf("b", testModel("b"))),
+					stripErr(b.CreateLeaf("c", testModel("c"))),
+				},
+			},
+		},
+	}
+	if _, err := b.UpdateSize(tea.WindowSizeMsg{Width: 5, Height: 3}); err != nil {
+		t.Fatal(err)
+	}
+	view := func(lines ...string) {
+		t.Helper()
+		if want, got := strings.Join(lines, NEWLINE), b.View(); got != want {
+			t.Errorf("expected:\n%s\nbut got:\n%s", want, got)
+		}
+	}
+
+	// the last size is used for the re-layout
+	if _, err := b.InsertAfter("a", stripErr(b.CreateLeaf("d", testModel("d")))); err != nil {
+		t.Fatal(err)
+	}
+	view(
+		"a│d│b",
+		" │ ├─",
+		" │ │c",
+	)
+
+	if _, err := b.Swap("a", "c"); err != nil {
+		t.Fatal(err)
+	}
+	view(
+		"c│d│b",
+		" │ ├─",
+		" │ │a",
+	)
+
+	if _, err := b.Remove("b"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := b.ModelMap["b"]; ok {
+		t.Error("the Model of a removed leaf should be removed from the ModelMap")
+	}
+	if node, err := b.Find("a"); err != nil || node != &b.LayoutTree.Children[2] {
+		t.Errorf("expected the single child to be collapsed into its parent, but got: %v", err)
+	}
+	view(
+		"c│d│a",
+		" │ │ ",
+		" │ │ ",
+	)
+
+	if _, err := b.Replace("d", stripErr(b.CreateLeaf("e", testModel("e")))); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := b.ModelMap["d"]; ok {
+		t.Error("the Model of a replaced leaf should be removed from the ModelMap")
+	}
+	view(
+		"c│e│a",
+		" │ │ ",
+		" │ │ ",
+	)
+
+	// the addresses of the leaves have to be unique
+	if _, err := b.InsertAfter("e", b.LayoutTree.Children[0]); err == nil {
+		t.Error("expected a error for inserting a leaf which is already in the Layout-tree")
+	}
+	wrapped := Node{Children: []Node{b.LayoutTree.Children[0], b.LayoutTree.Children[1]}}
+	if _, err := b.Replace("e", wrapped); err == nil {
+		t.Error("expected a error for replacing with a leaf which is already in the Layout-tree")
+	}
+	if _, err := b.Replace("e", Node{VerticalStacked: true, Children: []Node{b.LayoutTree.Children[1]}}); err != nil {
+		t.Errorf("expected the replaced leaf to be allowed in the node, but got: %v", err)
+	}
+	view(
+		"c│e│a",
+		" │ │ ",
+		" │ │ ",
+	)
+
+	if _, err := b.Remove("missing"); err == nil {
+		t.Error("expected a error for a missing address")
+	}
+
+	// only the ancestors of the removed leaf are collapsed and framed nodes are kept
+	b.LayoutTree = Node{
+		Children: []Node{
+			stripErr(b.CreateLeaf("a", testModel("a"))),
+			{Frame: &Frame{}, Children: []Node{stripErr(b.CreateLeaf("x", testModel("x")))}},
+			{Children: []Node{
+				stripErr(b.CreateLeaf("b", testModel("b"))),
+				stripErr(b.CreateLeaf("c", testModel("c"))),
+			}},
+		},
+	}
+	if _, err := b.UpdateSize(tea.WindowSizeMsg{Width: 11, Height: 3}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Remove("c"); err != nil {
+		t.Fatal(err)
+	}
+	if framed := b.LayoutTree.Children[1]; framed.Frame == nil || len(framed.Children) != 1 {
+		t.Error("expected the framed node to be kept")
+	}
+	if !b.LayoutTree.Children[2].IsLeaf() {
+		t.Error("expected the parent of the removed leaf to be collapsed")
+	}
+}
+
+func TestSplit(t *testing.T) {
+	b := Boxer{}
+	b.LayoutTree = stripErr(b.CreateLeaf("a", frameModel{}))
+	if _, err := b.UpdateSize(tea.WindowSizeMsg{Width: 9, Height: 3}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := b.Split("a", "b", frameModel{}, false, 0.5); err != nil {
+		t.Fatal(err)
+	}
+	// the parent has the same orientation, so the new leaf becomes a sibling
+	if _, err := b.Split("b", "c", frameModel{}, false, 0.5); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Split("c", "d", frameModel{}, true, 0.5); err != nil {
+		t.Fatal(err)
+	}
+	for address, want := range map[string]Rect{
+		"a": {X: 0, Y: 0, Width: 3, Height: 3},
+		"b": {X: 4, Y: 0, Width: 2, Height: 3},
+		"c": {X: 7, Y: 0, Width: 2, Height: 1},
+		"d": {X: 7, Y: 2, Width: 2, Height: 1},
+	} {
+		if got, _ := b.RectOf(address); got != want {
+			t.Errorf("expected '%s' at %s but got %s", address, want, got)
+		}
+		// the Models are resized right away
+		if got := b.ModelMap[address].(frameModel).size; got.Width != want.Width || got.Height != want.Height {
+			t.Errorf("expected the Model of '%s' to be resized to %s but got %dx%d", address, want, got.Width, got.Height)
+		}
+	}
+	if got := len(b.LayoutTree.Children); got != 3 {
+		t.Errorf("expected the second split to add a sibling, but the root has %d children", got)
+	}
+
+	if _, err := b.Split("a", "b", frameModel{}, false, 0.5); err == nil {
+		t.Error("expected a error since the new address is already used")
+	}
+	if _, err := b.Split("a", "e", frameModel{}, false, 1); err == nil {
+		t.Error("expected a error for a ratio which leaves no space")
+	}
+}